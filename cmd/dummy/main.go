@@ -6,17 +6,24 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/cristalhq/acmd"
 
+	"github.com/neotoolkit/dummy/internal/api"
 	"github.com/neotoolkit/dummy/internal/config"
 	"github.com/neotoolkit/dummy/internal/logger"
+	"github.com/neotoolkit/dummy/internal/openapi"
 	"github.com/neotoolkit/dummy/internal/parse"
+	"github.com/neotoolkit/dummy/internal/read"
 	"github.com/neotoolkit/dummy/internal/server"
+	"github.com/neotoolkit/dummy/internal/validate"
+	"github.com/neotoolkit/dummy/internal/watch"
 )
 
 const version = "0.2.1"
@@ -42,17 +49,153 @@ func run() error {
 				fs := flag.NewFlagSet("dummy", flag.ContinueOnError)
 				fs.StringVar(&cfg.Server.Port, "port", "8080", "")
 				fs.StringVar(&cfg.Logger.Level, "logger-level", "INFO", "")
+				fs.BoolVar(&cfg.Server.ValidateAnyMethodBody, "validate-any-method-body", false, "")
+				fs.StringVar(&cfg.Server.DuplicateKeys, "duplicate-keys", "ignore", "")
+				fs.StringVar(&cfg.Server.Validation, "validation", "strict", "")
+				fs.BoolVar(&cfg.Server.RootHealth, "root-health", false, "")
+				fs.StringVar(&cfg.Server.RootHealthBody, "root-health-body", "", "")
+				fs.BoolVar(&cfg.Server.Echo, "echo", false, "")
+				fs.Int64Var(&cfg.Server.Seed, "seed", 0, "")
+				fs.BoolVar(&cfg.Server.Stateful, "stateful", false, "")
+				fs.BoolVar(&cfg.Server.Paginate, "paginate", false, "")
+				fs.StringVar(&cfg.Server.Latency, "latency", "", "")
+				fs.IntVar(&cfg.Server.RateLimit, "rate-limit", 0, "")
+				fs.DurationVar(&cfg.Server.RateLimitWindow, "rate-limit-window", api.DefaultRateLimitWindow, "")
+				fs.Float64Var(&cfg.Server.ChaosRate, "chaos-rate", 0, "")
+				fs.StringVar(&cfg.Server.ChaosFailures, "chaos-failures", "", "")
+				fs.DurationVar(&cfg.Server.ChaosDelay, "chaos-delay", api.DefaultChaosDelay, "")
+				fs.StringVar(&cfg.Server.Upstream, "upstream", "", "")
+				fs.DurationVar(&cfg.Server.UpstreamTimeout, "upstream-timeout", 30*time.Second, "")
+				fs.BoolVar(&cfg.Server.Watch, "watch", false, "")
+				fs.BoolVar(&cfg.Server.Strict, "strict", false, "")
+				fs.BoolVar(&cfg.Server.MatchLog, "match-log", false, "")
+				fs.BoolVar(&cfg.Server.LegacyErrors, "legacy-errors", false, "")
+				allowedSchemes := fs.String("allowed-schemes", "http,https", "")
+				allowedHosts := fs.String("allowed-hosts", "", "")
+				corsOrigins := fs.String("cors-origins", "", "")
+				corsMethods := fs.String("cors-methods", "", "")
+				corsHeaders := fs.String("cors-headers", "", "")
+				basePaths := fs.String("base-path", "", "")
+				specTimeout := fs.Duration("spec-timeout", read.DefaultTimeout, "")
 				if err := fs.Parse(args[1:]); err != nil {
 					return err
 				}
 
-				api, err := parse.Parse(cfg.Server.Path)
+				cfg.Server.AllowedSchemes = split(*allowedSchemes)
+				cfg.Server.AllowedHosts = split(*allowedHosts)
+				cfg.Server.BasePaths = split(*basePaths)
+				cfg.Server.CORS = config.CORS{
+					Origins: split(*corsOrigins),
+					Methods: split(*corsMethods),
+					Headers: split(*corsHeaders),
+				}
+
+				opts := []parse.Option{
+					parse.WithReader(read.Reader{
+						Allowlist: read.Allowlist{
+							Schemes: cfg.Server.AllowedSchemes,
+							Hosts:   cfg.Server.AllowedHosts,
+						},
+						Timeout: *specTimeout,
+					}),
+				}
+
+				if cfg.Server.Seed != 0 {
+					opts = append(opts, parse.WithSeed(cfg.Server.Seed))
+				}
+
+				if cfg.Server.Strict {
+					opts = append(opts, parse.WithStrict())
+				}
+
+				for _, p := range cfg.Server.BasePaths {
+					opts = append(opts, parse.WithBasePath(p))
+				}
+
+				a, err := parse.ParseWithOptions(cfg.Server.Path, opts...)
 				if err != nil {
 					return fmt.Errorf("specification parse error: %w", err)
 				}
 
 				l := logger.NewLogger(cfg.Logger.Level)
-				h := server.NewHandlers(api, l)
+
+				a.ValidateAnyMethodBody = cfg.Server.ValidateAnyMethodBody
+				a.Logger = l
+				a.Echo = cfg.Server.Echo
+				a.Paginate = cfg.Server.Paginate
+
+				if cfg.Server.Stateful {
+					a.Store = api.NewStore(api.NewRand(time.Now().UnixNano()))
+				}
+
+				a.DuplicateKeys, err = api.ParseDuplicateKeysMode(cfg.Server.DuplicateKeys)
+				if err != nil {
+					return err
+				}
+
+				a.Validation, err = api.ParseValidationMode(cfg.Server.Validation)
+				if err != nil {
+					return err
+				}
+
+				a.Latency, err = api.ParseLatency(cfg.Server.Latency)
+				if err != nil {
+					return err
+				}
+
+				// operations may declare own rate limits, so limiter is set even without default limit
+				a.RateLimiter = api.NewRateLimiter(api.RateLimit{
+					Requests: cfg.Server.RateLimit,
+					Window:   cfg.Server.RateLimitWindow,
+				})
+
+				a.Chaos = api.Chaos{
+					Rate:  cfg.Server.ChaosRate,
+					Delay: cfg.Server.ChaosDelay,
+				}
+
+				a.Chaos.Failures, err = api.ParseFailures(cfg.Server.ChaosFailures)
+				if err != nil {
+					return err
+				}
+
+				if cfg.Server.Upstream != "" {
+					a.Upstream, err = url.Parse(cfg.Server.Upstream)
+					if err != nil {
+						return fmt.Errorf("upstream parse error: %w", err)
+					}
+
+					a.UpstreamTimeout = cfg.Server.UpstreamTimeout
+				}
+
+				h := server.NewHandlers(a, l)
+				if cfg.Server.Seed != 0 {
+					h.Rand = api.NewRand(cfg.Server.Seed)
+				}
+
+				if cfg.Server.MatchLog {
+					h.MatchLog = os.Stdout
+				}
+
+				if cfg.Server.Watch {
+					h.Live = api.NewHolder(a)
+
+					watchCtx, stopWatch := context.WithCancel(ctx)
+					defer stopWatch()
+
+					go func() {
+						const interval = time.Second
+
+						load := func() (api.API, error) {
+							return parse.ParseWithOptions(cfg.Server.Path, opts...)
+						}
+
+						if err := watch.Reload(watchCtx, cfg.Server.Path, interval, h.Live, load, l); err != nil {
+							l.Warn().Err(err).Msg("watch specification")
+						}
+					}()
+				}
+
 				s := server.NewServer(cfg.Server, l, h)
 
 				go func() {
@@ -75,6 +218,47 @@ func run() error {
 				return s.Stop(ctx)
 			},
 		},
+		{
+			Name:        "validate",
+			Alias:       "v",
+			Description: "validate specification without running mock server",
+			Do: func(ctx context.Context, args []string) error {
+				fs := flag.NewFlagSet("dummy", flag.ContinueOnError)
+				allowedSchemes := fs.String("allowed-schemes", "http,https", "")
+				allowedHosts := fs.String("allowed-hosts", "", "")
+				specTimeout := fs.Duration("spec-timeout", read.DefaultTimeout, "")
+				if err := fs.Parse(args[1:]); err != nil {
+					return err
+				}
+
+				file, err := read.Reader{
+					Allowlist: read.Allowlist{
+						Schemes: split(*allowedSchemes),
+						Hosts:   split(*allowedHosts),
+					},
+					Timeout: *specTimeout,
+				}.Read(args[0])
+				if err != nil {
+					return err
+				}
+
+				oapi, err := openapi.Parse(file)
+				if err != nil {
+					return fmt.Errorf("specification parse error: %w", err)
+				}
+
+				problems := validate.Validate(oapi)
+				if len(problems) == 0 {
+					return nil
+				}
+
+				for _, p := range problems {
+					fmt.Fprintln(os.Stdout, p)
+				}
+
+				return &validate.Error{Problems: problems}
+			},
+		},
 	}
 
 	r := acmd.RunnerOf(cmds, acmd.Config{
@@ -84,3 +268,12 @@ func run() error {
 
 	return r.Run()
 }
+
+// split returns comma-separated values, empty string results in no values
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, ",")
+}
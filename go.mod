@@ -7,9 +7,9 @@ require (
 	github.com/goccy/go-yaml v1.9.5
 	github.com/lamoda/gonkey v1.13.2
 	github.com/neotoolkit/faker v0.1.1
-	github.com/neotoolkit/openapi v0.3.1
 	github.com/rs/zerolog v1.26.1
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
 
 require (
@@ -28,5 +28,4 @@ require (
 	golang.org/x/sys v0.0.0-20220114195835-da31bd327af9 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+)
+
+// mediaRange is media range of Accept header with quality value
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+type mediaRanges []mediaRange
+
+// parseAccept returns media ranges of Accept header value, e.g. `application/xml, application/json;q=0.5`
+func parseAccept(accept string) mediaRanges {
+	var ranges mediaRanges
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err == nil {
+				quality = q
+			}
+		}
+
+		ranges = append(ranges, mediaRange{
+			mediaType: mediaType,
+			quality:   quality,
+		})
+	}
+
+	return ranges
+}
+
+// quality returns quality value of the most specific media range matching media type,
+// zero means media type is not acceptable
+func (ranges mediaRanges) quality(mediaType string) float64 {
+	if mediaType == "" {
+		return 0
+	}
+
+	mediaType = strings.ToLower(mediaType)
+
+	var (
+		quality     float64
+		specificity = -1
+	)
+
+	for _, r := range ranges {
+		s := r.specificity(mediaType)
+		if s > specificity {
+			quality, specificity = r.quality, s
+		}
+	}
+
+	return quality
+}
+
+// specificity returns 2 for exact match, 1 for `type/*` match, 0 for `*/*` match and -1 when media type does not match
+func (r mediaRange) specificity(mediaType string) int {
+	switch {
+	case r.mediaType == mediaType:
+		return 2
+	case r.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(r.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(r.mediaType, "*")):
+		return 1
+	default:
+		return -1
+	}
+}
@@ -1,49 +1,304 @@
 package api
 
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neotoolkit/dummy/internal/logger"
+)
+
 // API -.
 type API struct {
 	Operations []Operation
+	// ValidateAnyMethodBody enables body validation for any method which declares request body,
+	// not only for POST, PUT and PATCH
+	ValidateAnyMethodBody bool
+	// DuplicateKeys defines handling of duplicate keys in request body
+	DuplicateKeys DuplicateKeysMode
+	// Validation defines handling of invalid request body, it is rejected by default
+	Validation ValidationMode
+	// Logger is used for warnings, may be nil
+	Logger *logger.Logger
+	// Echo populates object response properties with same-named fields of request body
+	Echo bool
+	// Store enables stateful mode for collection endpoints, API is stateless when nil
+	Store *Store
+	// Paginate fills pagination envelopes of list responses, i.e. objects with array `data` property,
+	// with page of items and metadata requested by `page` and `limit` query parameters
+	Paginate bool
+	// Latency is simulated delay of responses
+	Latency Latency
+	// RateLimiter rejects requests exceeding rate limits, requests are not limited when nil
+	RateLimiter *RateLimiter
+	// Clock is source of current time of rate limiting, it is Clock of Builder, SystemClock is used when nil
+	Clock Clock
+	// Chaos injects failures into responses, no failures are injected by default
+	Chaos Chaos
+	// Upstream is URL of server receiving proxied requests of not specified operations,
+	// requests are not proxied when nil
+	Upstream *url.URL
+	// UpstreamTimeout limits duration of proxied requests, no limit when zero
+	UpstreamTimeout time.Duration
+
+	// router indexes Operations by path segments, Operations are scanned when nil
+	router *router
 }
 
 // Operation -.
 type Operation struct {
-	Method    string
-	Path      string
-	Body      map[string]FieldType
-	Responses []Response
+	Method string
+	Path   string
+	// Query contains required query parameters with required values, empty value requires presence only
+	Query map[string]string
+	// PathParams contain constraints of path parameters, path with violating values does not match operation
+	PathParams map[string]FieldType
+	// QueryParams, HeaderParams and CookieParams contain types of declared parameters,
+	// request without required ones is invalid
+	QueryParams  map[string]FieldType
+	HeaderParams map[string]FieldType
+	CookieParams map[string]FieldType
+	Body         map[string]FieldType
+	// BodyMinProperties and BodyMaxProperties limit count of request body properties, zero means no limit
+	BodyMinProperties int
+	BodyMaxProperties int
+	// BodyAdditionalProperties validates request body properties not listed in Body, they are not validated when nil
+	BodyAdditionalProperties *FieldType
+	// BodyStrict rejects request body properties not listed in Body, i.e. `additionalProperties: false`
+	BodyStrict bool
+	// BodyDiscriminator selects validation rules of request body by discriminator field, nil for body without variants
+	BodyDiscriminator *Discriminator
+	// BodyMediaTypes contain request body validation rules by media type of body declaring several media types,
+	// rules of operation itself apply to JSON, URL-encoded form or multipart form body, in order of preference
+	BodyMediaTypes map[string]Operation
+	// BodyEncoding contains allowed content types of multipart parts by property name, e.g. `image/*`
+	BodyEncoding map[string]string
+	Responses    []Response
+	CORS         *CORS
+	// Security contains alternative security requirements, operation is not secured when empty
+	Security []SecurityRequirement
+	// RateLimit overrides rate limit of RateLimiter for operation, e.g. by `x-rate-limit` extension
+	RateLimit *RateLimit
+}
+
+// Discriminator selects variant of request body by value of field
+type Discriminator struct {
+	Field string
+	// Variants contain request body validation rules by field value
+	Variants map[string]Operation
+}
+
+// CORS is operation specific CORS configuration
+type CORS struct {
+	Origins []string
+	Methods []string
+	Headers []string
 }
 
 // FieldType -.
 type FieldType struct {
 	Required bool
 	Type     string
+	// Enum contains allowed values, any value is allowed when empty
+	Enum []interface{}
+	// MinLength, MaxLength and Pattern constrain string values, MaxLength and Pattern are not checked when nil
+	MinLength int
+	MaxLength *int
+	Pattern   *regexp.Regexp
+	// Format constrains string values, e.g. date-time or email, unknown formats are not checked
+	Format string
+	// Nullable allows explicit null value
+	Nullable bool
+	// Bounds constrain numeric values
+	Bounds Bounds
+	// Properties validate properties of object value, Items validates items of array value
+	Properties map[string]FieldType
+	Items      *FieldType
+}
+
+const (
+	// MediaTypeJSON is media type of JSON responses
+	MediaTypeJSON = "application/json"
+	// MediaTypeXML is media type of XML responses
+	MediaTypeXML = "application/xml"
+	// MediaTypeOctetStream is media type of binary responses
+	MediaTypeOctetStream = "application/octet-stream"
+	// MediaTypeForm is media type of URL-encoded form request bodies
+	MediaTypeForm = "application/x-www-form-urlencoded"
+	// MediaTypeMultipart is media type of multipart form request bodies, e.g. file uploads
+	MediaTypeMultipart = "multipart/form-data"
+)
+
+// IsXML reports whether media type is XML one, e.g. `application/xml`, `text/xml` or `application/problem+xml`
+func IsXML(mediaType string) bool {
+	return mediaType == MediaTypeXML || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
 }
 
 // Response -.
 type Response struct {
 	StatusCode int
-	MediaType  string
-	Schema     Schema
-	Example    interface{}
-	Examples   map[string]interface{}
+	// Default is set for `default` response covering status codes not specified individually,
+	// its status code is 200 unless other status code is requested
+	Default bool
+	// Range is class of status codes of response covering range of them, e.g. 4 for `4XX`, zero for single status code.
+	// Status code of range response is first one of range, e.g. 400, unless other status code of range is requested.
+	Range     int
+	MediaType string
+	Schema    Schema
+	Example   interface{}
+	Examples  map[string]interface{}
+	// ExampleWeights are weights of named examples for weighted selection
+	ExampleWeights map[string]float64
+	// RequestBody is decoded body of matched request, set in echo mode only
+	RequestBody map[string]interface{}
+	// ExampleKey is name of example preferred by client, set by FindResponse
+	ExampleKey string
+	// Value overrides examples, e.g. stored resource in stateful mode
+	Value interface{}
+	// XMLName is name of root element of XML response
+	XMLName string
+	// Request contains values of matched request substituted into example placeholders, set by FindResponse
+	Request *RequestValues
+	// Headers are response headers declared in specification, sorted by name
+	Headers []Header
+	// Variants are schemas of oneOf or anyOf branches of response schema by variant name,
+	// i.e. discriminator value, name of referenced schema or index of branch
+	Variants map[string]Schema
+}
+
+// Header is response header declared in specification
+type Header struct {
+	Name    string
+	Example interface{}
+	Schema  Schema
+}
+
+// Value returns example of header or example of its schema, array elements are comma-separated.
+// Empty string is returned for header without example and schema.
+func (h Header) Value() string {
+	example := h.Example
+	if nil == example && h.Schema != nil {
+		example = h.Schema.ExampleValue()
+	}
+
+	if nil == example {
+		return ""
+	}
+
+	if items, ok := example.([]interface{}); ok {
+		values := make([]string, len(items))
+		for i, item := range items {
+			values[i] = fmt.Sprint(item)
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	return fmt.Sprint(example)
+}
+
+// WithVariant returns response of variant schema, examples of response are dropped since they may be
+// of other variant. Response is returned as is for unknown variant.
+func (r Response) WithVariant(name string) Response {
+	schema, ok := r.Variants[name]
+	if !ok {
+		return r
+	}
+
+	r.Schema, r.Example, r.Examples, r.ExampleWeights = schema, nil, nil, nil
+
+	return r
+}
+
+// WeightedExampleKey returns name of example chosen by example weights.
+// Empty key is returned when weights are not specified.
+func (r Response) WeightedExampleKey(rnd *rand.Rand) string {
+	if len(r.ExampleWeights) == 0 || nil == rnd {
+		return ""
+	}
+
+	keys := make([]string, 0, len(r.ExampleWeights))
+
+	var total float64
+
+	for key, weight := range r.ExampleWeights {
+		keys = append(keys, key)
+		total += weight
+	}
+
+	if total <= 0 {
+		return ""
+	}
+
+	sort.Strings(keys)
+
+	n := rnd.Float64() * total
+
+	for _, key := range keys {
+		n -= r.ExampleWeights[key]
+		if n < 0 {
+			return key
+		}
+	}
+
+	return keys[len(keys)-1]
 }
 
 // ExampleValue -.
 func (r Response) ExampleValue(key string) interface{} {
+	if r.Value != nil {
+		return r.Value
+	}
+
 	if nil == r.Schema {
 		return nil
 	}
 
 	example, ok := r.Examples[key]
-	if ok {
-		return example
+	if !ok || nil == example {
+		example = r.Example
 	}
 
-	if r.Example != nil {
-		return r.Example
+	if nil == example {
+		example = r.Schema.ExampleValue()
 	}
 
-	return r.Schema.ExampleValue()
+	return r.Request.render(r.echo(example))
+}
+
+// echo returns object value with properties declared by response schema taken from request body
+func (r Response) echo(value interface{}) interface{} {
+	if len(r.RequestBody) == 0 {
+		return value
+	}
+
+	obj, ok := r.Schema.(ObjectSchema)
+	if !ok {
+		return value
+	}
+
+	v, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	res := make(map[string]interface{}, len(v))
+	for k, val := range v {
+		res[k] = val
+	}
+
+	for k := range obj.Properties {
+		if val, ok := r.RequestBody[k]; ok {
+			res[k] = val
+		}
+	}
+
+	return res
 }
 
 // Schema -.
@@ -53,7 +308,8 @@ type Schema interface {
 
 // BooleanSchema -.
 type BooleanSchema struct {
-	Example bool
+	Example  bool
+	Nullable bool
 }
 
 // ExampleValue -.
@@ -64,16 +320,26 @@ func (b BooleanSchema) ExampleValue() interface{} {
 // IntSchema -.
 type IntSchema struct {
 	Example int64
+	// Unsigned is example greater than math.MaxInt64, e.g. uint64 identifier, Example is used when it is zero
+	Unsigned uint64
+	Nullable bool
+	Bounds   Bounds
 }
 
 // ExampleValue -.
 func (i IntSchema) ExampleValue() interface{} {
+	if i.Unsigned > 0 {
+		return i.Unsigned
+	}
+
 	return i.Example
 }
 
 // FloatSchema -.
 type FloatSchema struct {
-	Example float64
+	Example  float64
+	Nullable bool
+	Bounds   Bounds
 }
 
 // ExampleValue -.
@@ -83,7 +349,8 @@ func (f FloatSchema) ExampleValue() interface{} {
 
 // StringSchema -.
 type StringSchema struct {
-	Example string
+	Example  string
+	Nullable bool
 }
 
 // ExampleValue -.
@@ -93,8 +360,15 @@ func (s StringSchema) ExampleValue() interface{} {
 
 // ArraySchema -.
 type ArraySchema struct {
-	Type    Schema
-	Example []interface{}
+	Type     Schema
+	Example  []interface{}
+	Nullable bool
+	// Generated are elements of large generated array produced on demand, nil for array with Example
+	Generated *Items
+	// XMLItemName is XML element name of elements, i.e. `xml.name` of items, name of array is used when empty
+	XMLItemName string
+	// XMLWrapped wraps XML elements of elements into element of array name, i.e. `xml.wrapped`
+	XMLWrapped bool
 }
 
 // ExampleValue -.
@@ -103,6 +377,14 @@ func (a ArraySchema) ExampleValue() interface{} {
 		return a.Example
 	}
 
+	if a.Generated != nil {
+		return *a.Generated
+	}
+
+	if nil == a.Type {
+		return []interface{}{}
+	}
+
 	return []interface{}{a.Type.ExampleValue()}
 }
 
@@ -110,9 +392,20 @@ func (a ArraySchema) ExampleValue() interface{} {
 type ObjectSchema struct {
 	Properties map[string]Schema
 	Example    map[string]interface{}
+	Nullable   bool
+	// XMLNames are XML element names of properties overridden by `xml.name`
+	XMLNames map[string]string
+	// XMLAttributes are properties written as XML attributes, i.e. `xml.attribute`
+	XMLAttributes map[string]bool
+	// AdditionalProperties is schema of values of properties not listed in Properties, nil when not declared
+	AdditionalProperties Schema
 }
 
-// ExampleValue -.
+// additionalProperties is count of synthetic properties of free-form object example
+const additionalProperties = 2
+
+// ExampleValue returns example of object. Free-form object without properties, i.e. map,
+// gets synthetic properties `additionalProp1`, `additionalProp2` following additional properties schema.
 func (o ObjectSchema) ExampleValue() interface{} {
 	if len(o.Example) > 0 {
 		return o.Example
@@ -124,9 +417,33 @@ func (o ObjectSchema) ExampleValue() interface{} {
 		example[key] = propSchema.ExampleValue()
 	}
 
+	if len(o.Properties) == 0 && o.AdditionalProperties != nil {
+		for i := 1; i <= additionalProperties; i++ {
+			example["additionalProp"+strconv.Itoa(i)] = o.AdditionalProperties.ExampleValue()
+		}
+	}
+
 	return example
 }
 
+// NullSchema is schema of nullable value generated as null
+type NullSchema struct{}
+
+// ExampleValue -.
+func (NullSchema) ExampleValue() interface{} {
+	return nil
+}
+
+// BinarySchema -.
+type BinarySchema struct {
+	Example []byte
+}
+
+// ExampleValue -.
+func (b BinarySchema) ExampleValue() interface{} {
+	return b.Example
+}
+
 // FakerSchema -.
 type FakerSchema struct {
 	Example interface{}
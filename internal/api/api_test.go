@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,6 +60,11 @@ func TestSchema_ExampleValue(t *testing.T) {
 			schema: api.ArraySchema{Type: api.StringSchema{}},
 			want:   []interface{}{""},
 		},
+		{
+			name:   "array: without items",
+			schema: api.ArraySchema{},
+			want:   []interface{}{},
+		},
 		{
 			name:   "array: with int example",
 			schema: api.ArraySchema{Example: []interface{}{4, 2}},
@@ -210,3 +216,142 @@ func TestResponse_ExampleValue(t *testing.T) {
 		})
 	}
 }
+
+func TestResponse_WeightedExampleKey(t *testing.T) {
+	const n = 10000
+
+	r := api.Response{
+		ExampleWeights: map[string]float64{
+			"ok":       3,
+			"degraded": 1,
+		},
+	}
+
+	rnd := api.NewRand(42)
+	got := make(map[string]int, len(r.ExampleWeights))
+
+	for i := 0; i < n; i++ {
+		got[r.WeightedExampleKey(rnd)]++
+	}
+
+	require.Len(t, got, 2)
+	require.InDelta(t, 0.75, float64(got["ok"])/n, 0.02)
+	require.InDelta(t, 0.25, float64(got["degraded"])/n, 0.02)
+
+	require.Equal(t, "", api.Response{}.WeightedExampleKey(rnd))
+	require.Equal(t, "", r.WeightedExampleKey(nil))
+}
+
+func TestHeader_Value(t *testing.T) {
+	tests := []struct {
+		name   string
+		header api.Header
+		want   string
+	}{
+		{
+			name:   "example",
+			header: api.Header{Example: "/users/1", Schema: api.StringSchema{Example: "/users/2"}},
+			want:   "/users/1",
+		},
+		{
+			name:   "schema example",
+			header: api.Header{Schema: api.IntSchema{Example: 100}},
+			want:   "100",
+		},
+		{
+			name:   "array",
+			header: api.Header{Example: []interface{}{"a", "b"}},
+			want:   "a,b",
+		},
+		{
+			name:   "no example",
+			header: api.Header{},
+			want:   "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.header.Value())
+		})
+	}
+}
+
+func TestOperationConflictError(t *testing.T) {
+	got := &api.OperationConflictError{Method: http.MethodGet, Path: "/users"}
+
+	require.Equal(t, got.Error(), "conflicting operation GET /users")
+}
+
+func TestAPI_Merge(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     api.Operation
+		b     api.Operation
+		error error
+	}{
+		{
+			name:  "same path",
+			a:     api.Operation{Method: http.MethodGet, Path: "/users"},
+			b:     api.Operation{Method: http.MethodGet, Path: "/users"},
+			error: &api.OperationConflictError{Method: http.MethodGet, Path: "/users"},
+		},
+		{
+			name:  "other parameter name",
+			a:     api.Operation{Method: http.MethodGet, Path: "/users/{id}"},
+			b:     api.Operation{Method: http.MethodGet, Path: "/users/{userId}"},
+			error: &api.OperationConflictError{Method: http.MethodGet, Path: "/users/{userId}"},
+		},
+		{
+			name:  "same query",
+			a:     api.Operation{Method: http.MethodGet, Path: "/search", Query: map[string]string{"type": "a", "sort": "asc"}},
+			b:     api.Operation{Method: http.MethodGet, Path: "/search", Query: map[string]string{"sort": "asc", "type": "a"}},
+			error: &api.OperationConflictError{Method: http.MethodGet, Path: "/search"},
+		},
+		{
+			name: "other query",
+			a:    api.Operation{Method: http.MethodGet, Path: "/search", Query: map[string]string{"type": "a"}},
+			b:    api.Operation{Method: http.MethodGet, Path: "/search", Query: map[string]string{"type": "b"}},
+		},
+		{
+			name: "other method",
+			a:    api.Operation{Method: http.MethodGet, Path: "/users/{id}"},
+			b:    api.Operation{Method: http.MethodDelete, Path: "/users/{userId}"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api.API{Operations: []api.Operation{tc.a}}
+
+			got, err := a.Merge(api.API{Operations: []api.Operation{tc.b}})
+			if tc.error != nil {
+				require.Equal(t, tc.error, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []api.Operation{tc.a, tc.b}, got.Operations)
+		})
+	}
+}
+
+func TestIsXML(t *testing.T) {
+	tests := []struct {
+		mediaType string
+		want      bool
+	}{
+		{mediaType: "application/xml", want: true},
+		{mediaType: "text/xml", want: true},
+		{mediaType: "application/problem+xml", want: true},
+		{mediaType: "application/json", want: false},
+		{mediaType: "text/plain", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.mediaType, func(t *testing.T) {
+			require.Equal(t, tc.want, api.IsXML(tc.mediaType))
+		})
+	}
+}
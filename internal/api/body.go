@@ -0,0 +1,374 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DuplicateKeysMode defines handling of duplicate keys in request body
+type DuplicateKeysMode int
+
+const (
+	// DuplicateKeysIgnore -.
+	DuplicateKeysIgnore DuplicateKeysMode = iota
+	// DuplicateKeysWarn -.
+	DuplicateKeysWarn
+	// DuplicateKeysStrict -.
+	DuplicateKeysStrict
+)
+
+// DuplicateKeysModeError -.
+type DuplicateKeysModeError struct {
+	Mode string
+}
+
+// Error -.
+func (e *DuplicateKeysModeError) Error() string {
+	return "unknown duplicate keys mode: " + e.Mode
+}
+
+// ParseDuplicateKeysMode returns DuplicateKeysMode by name
+func ParseDuplicateKeysMode(mode string) (DuplicateKeysMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "ignore":
+		return DuplicateKeysIgnore, nil
+	case "warn":
+		return DuplicateKeysWarn, nil
+	case "strict":
+		return DuplicateKeysStrict, nil
+	default:
+		return DuplicateKeysIgnore, &DuplicateKeysModeError{Mode: mode}
+	}
+}
+
+// DuplicateKeyError -.
+type DuplicateKeyError struct {
+	Keys []string
+}
+
+// Error -.
+func (e *DuplicateKeyError) Error() string {
+	return "duplicate keys in request body: " + strings.Join(e.Keys, ", ")
+}
+
+// PartContentTypeError is returned when content type of multipart part is not allowed by encoding of property
+type PartContentTypeError struct {
+	Field       string
+	ContentType string
+	// Allowed is comma-separated list of allowed content types
+	Allowed string
+}
+
+// Error -.
+func (e *PartContentTypeError) Error() string {
+	return "content type " + e.ContentType + " of part " + e.Field + " is not allowed, expected " + e.Allowed
+}
+
+// DuplicateKeys returns keys declared more than once in the same JSON object.
+// Nested keys are joined by dot.
+func DuplicateKeys(data []byte) ([]string, error) {
+	var duplicates []string
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if err := scanDuplicateKeys(dec, "", &duplicates); err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+func scanDuplicateKeys(dec *json.Decoder, prefix string, duplicates *[]string) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := t.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		keys := make(map[string]struct{})
+
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key, _ := t.(string)
+
+			if _, ok := keys[key]; ok {
+				*duplicates = append(*duplicates, prefix+key)
+			}
+
+			keys[key] = struct{}{}
+
+			if err := scanDuplicateKeys(dec, prefix+key+".", duplicates); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for dec.More() {
+			if err := scanDuplicateKeys(dec, prefix, duplicates); err != nil {
+				return err
+			}
+		}
+	}
+
+	// closing delimiter
+	_, err = dec.Token()
+
+	return err
+}
+
+func (a API) decodeBody(body io.Reader) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+
+	if nil == body {
+		return res, nil
+	}
+
+	if a.DuplicateKeys != DuplicateKeysIgnore {
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+
+		// syntax errors are reported by decoder below
+		duplicates, _ := DuplicateKeys(data)
+		if len(duplicates) > 0 {
+			if a.DuplicateKeys == DuplicateKeysStrict {
+				return nil, &DuplicateKeyError{Keys: duplicates}
+			}
+
+			if a.Logger != nil {
+				a.Logger.Warn().Strs("keys", duplicates).Msg("duplicate keys in request body")
+			}
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	err := json.NewDecoder(body).Decode(&res)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// mediaTypeOf returns media type of content type without parameters like charset, empty for invalid content type
+func mediaTypeOf(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+// isForm reports whether content type is URL-encoded form, parameters like charset are ignored
+func isForm(contentType string) bool {
+	return mediaTypeOf(contentType) == MediaTypeForm
+}
+
+// isMultipart reports whether content type is multipart form
+func isMultipart(contentType string) bool {
+	return mediaTypeOf(contentType) == MediaTypeMultipart
+}
+
+// decodeForm returns URL-encoded form values of body. Values of integer, number and boolean fields
+// are converted to the same types as decoded JSON values, so they are validated alike.
+// Repeated keys result in array of values.
+func decodeForm(body io.Reader, fields map[string]FieldType) (map[string]interface{}, error) {
+	if nil == body {
+		return make(map[string]interface{}), nil
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return formValues(values, fields), nil
+}
+
+// decodeMultipart returns values of multipart form body like decodeForm, value of file part is its file name.
+// Parts of JSON content type and parts of object fields are decoded as JSON.
+// Content types of parts are checked against encoding of operation.
+func decodeMultipart(body io.Reader, contentType string, o Operation) (map[string]interface{}, error) {
+	if nil == body {
+		return make(map[string]interface{}), nil
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(url.Values)
+	objects := make(map[string]interface{})
+
+	r := multipart.NewReader(body, params["boundary"])
+
+	for {
+		part, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := part.FormName()
+
+		if err := checkPartContentType(part, o.BodyEncoding[name]); err != nil {
+			return nil, err
+		}
+
+		if part.FileName() != "" {
+			values.Add(name, part.FileName())
+
+			continue
+		}
+
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		if isJSONPart(part, o.Body[name]) {
+			var v interface{}
+			if err := json.Unmarshal(data, &v); err == nil {
+				objects[name] = v
+
+				continue
+			}
+		}
+
+		values.Add(name, string(data))
+	}
+
+	res := formValues(values, o.Body)
+	for k, v := range objects {
+		res[k] = v
+	}
+
+	return res, nil
+}
+
+// checkPartContentType returns error when content type of part is not one of allowed ones, any is allowed when empty.
+// Part without content type is plain text, or binary data when it is file.
+func checkPartContentType(part *multipart.Part, allowed string) error {
+	if allowed == "" {
+		return nil
+	}
+
+	contentType := part.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+		if part.FileName() != "" {
+			contentType = "application/octet-stream"
+		}
+	}
+
+	if parseAccept(allowed).quality(mediaTypeOf(contentType)) > 0 {
+		return nil
+	}
+
+	return &PartContentTypeError{
+		Field:       part.FormName(),
+		ContentType: contentType,
+		Allowed:     allowed,
+	}
+}
+
+// isJSONPart reports whether part is JSON value, i.e. it has JSON content type or it is value of object field
+func isJSONPart(part *multipart.Part, field FieldType) bool {
+	return mediaTypeOf(part.Header.Get("Content-Type")) == MediaTypeJSON || field.Type == "object"
+}
+
+// formValues returns form values converted to field types, repeated keys result in array of values.
+// Values of array field always result in array, its items are converted to type of items.
+func formValues(values url.Values, fields map[string]FieldType) map[string]interface{} {
+	res := make(map[string]interface{}, len(values))
+
+	for key, vals := range values {
+		field := fields[key]
+
+		if len(vals) == 1 && field.Type != "array" {
+			res[key] = formValue(vals[0], field.Type)
+
+			continue
+		}
+
+		var itemType string
+		if field.Items != nil {
+			itemType = field.Items.Type
+		}
+
+		arr := make([]interface{}, len(vals))
+		for i, v := range vals {
+			arr[i] = formValue(v, itemType)
+		}
+
+		res[key] = arr
+	}
+
+	return res
+}
+
+// paramValue returns parameter value converted to field type, array items are comma-separated, e.g. `1,2,3`
+func paramValue(value string, field FieldType) interface{} {
+	if field.Type != "array" {
+		return formValue(value, field.Type)
+	}
+
+	var itemType string
+	if field.Items != nil {
+		itemType = field.Items.Type
+	}
+
+	items := strings.Split(value, ",")
+
+	arr := make([]interface{}, len(items))
+	for i, item := range items {
+		arr[i] = formValue(item, itemType)
+	}
+
+	return arr
+}
+
+// formValue returns form value converted to field type, value is left as is when it is not convertible
+func formValue(value, fieldType string) interface{} {
+	switch fieldType {
+	case "integer", "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+
+	return value
+}
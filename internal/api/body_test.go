@@ -0,0 +1,421 @@
+package api_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/parse"
+)
+
+func TestDuplicateKeyError(t *testing.T) {
+	got := &api.DuplicateKeyError{
+		Keys: []string{"a", "b.c"},
+	}
+
+	require.Equal(t, got.Error(), "duplicate keys in request body: a, b.c")
+}
+
+func TestPartContentTypeError(t *testing.T) {
+	got := &api.PartContentTypeError{
+		Field:       "file",
+		ContentType: "text/plain",
+		Allowed:     "image/*",
+	}
+
+	require.Equal(t, got.Error(), "content type text/plain of part file is not allowed, expected image/*")
+}
+
+func TestParseDuplicateKeysMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		want api.DuplicateKeysMode
+		err  error
+	}{
+		{
+			name: "default",
+			mode: "",
+			want: api.DuplicateKeysIgnore,
+			err:  nil,
+		},
+		{
+			name: "warn",
+			mode: "warn",
+			want: api.DuplicateKeysWarn,
+			err:  nil,
+		},
+		{
+			name: "strict",
+			mode: "STRICT",
+			want: api.DuplicateKeysStrict,
+			err:  nil,
+		},
+		{
+			name: "unknown",
+			mode: "reject",
+			want: api.DuplicateKeysIgnore,
+			err:  &api.DuplicateKeysModeError{Mode: "reject"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := api.ParseDuplicateKeysMode(tc.mode)
+			if err != nil {
+				require.EqualError(t, err, tc.err.Error())
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "without duplicates",
+			data: `{"a": 1, "b": {"a": 2}, "c": [{"a": 3}, {"a": 4}]}`,
+			want: nil,
+		},
+		{
+			name: "top level duplicate",
+			data: `{"a": 1, "a": 2}`,
+			want: []string{"a"},
+		},
+		{
+			name: "nested duplicate",
+			data: `{"a": {"b": 1, "b": 2}}`,
+			want: []string{"a.b"},
+		},
+		{
+			name: "duplicate in array item",
+			data: `{"a": [{"b": 1, "b": 2}]}`,
+			want: []string{"a.b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := api.DuplicateKeys([]byte(tc.data))
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAPI_FindResponse_DuplicateKeys(t *testing.T) {
+	a := api.API{
+		Operations: []api.Operation{
+			{
+				Method: http.MethodPost,
+				Path:   "/users",
+				Body: map[string]api.FieldType{
+					"name": {Required: true, Type: "string"},
+				},
+				Responses: []api.Response{
+					{StatusCode: http.StatusCreated},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		mode api.DuplicateKeysMode
+		err  error
+	}{
+		{
+			name: "ignore",
+			mode: api.DuplicateKeysIgnore,
+			err:  nil,
+		},
+		{
+			name: "warn",
+			mode: api.DuplicateKeysWarn,
+			err:  nil,
+		},
+		{
+			name: "strict",
+			mode: api.DuplicateKeysStrict,
+			err:  &api.DuplicateKeyError{Keys: []string{"name"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a.DuplicateKeys = tc.mode
+
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/users",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(`{"name": "Elon", "name": "Sergey"}`)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusCreated, got.StatusCode)
+		})
+	}
+}
+
+func TestAPI_FindResponse_Form(t *testing.T) {
+	a, err := parse.Parse("./testdata/form.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		err         error
+	}{
+		{
+			name:        "required fields",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=elon&password=mars&remember=true&attempts=3",
+			err:         nil,
+		},
+		{
+			name:        "content type with charset",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			body:        "username=elon%40example.com&password=mars",
+			err:         nil,
+		},
+		{
+			name:        "required field is absent",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=elon",
+			err:         api.ErrEmptyRequireField,
+		},
+		{
+			name:        "not integer value",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=elon&password=mars&attempts=many",
+			err:         &api.FieldTypeError{Field: "attempts", Type: "integer"},
+		},
+		{
+			name:        "single value of array field",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=elon&password=mars&scopes=1",
+			err:         nil,
+		},
+		{
+			name:        "repeated values of array field",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=elon&password=mars&scopes=1&scopes=2",
+			err:         nil,
+		},
+		{
+			name:        "not integer item of array field",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "username=elon&password=mars&scopes=1&scopes=all",
+			err:         &api.FieldTypeError{Field: "scopes[1]", Type: "integer"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:        "/login",
+				Method:      http.MethodPost,
+				ContentType: tc.contentType,
+				Body:        io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPI_FindResponse_MediaTypes(t *testing.T) {
+	a, err := parse.Parse("./testdata/media-types.yml")
+	require.NoError(t, err)
+
+	multipartBody := func(fields map[string]string, file, fileType string) (string, string) {
+		var buf bytes.Buffer
+
+		w := multipart.NewWriter(&buf)
+
+		for name, value := range fields {
+			require.NoError(t, w.WriteField(name, value))
+		}
+
+		if file != "" {
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", `form-data; name="file"; filename="`+file+`"`)
+			header.Set("Content-Type", fileType)
+
+			part, err := w.CreatePart(header)
+			require.NoError(t, err)
+
+			_, err = part.Write([]byte("content"))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, w.Close())
+
+		return w.FormDataContentType(), buf.String()
+	}
+
+	withFile, withFileBody := multipartBody(map[string]string{"title": "report"}, "report.pdf", "application/pdf")
+	withImage, withImageBody := multipartBody(map[string]string{"title": "scan"}, "scan.png", "image/png")
+	withText, withTextBody := multipartBody(map[string]string{"title": "notes"}, "notes.txt", "text/plain")
+	withoutFile, withoutFileBody := multipartBody(map[string]string{"title": "report"}, "", "")
+	withMeta, withMetaBody := multipartBody(map[string]string{"meta": `{"pages":3}`}, "report.pdf", "application/pdf")
+	wrongMeta, wrongMetaBody := multipartBody(map[string]string{"meta": `{"pages":"many"}`}, "report.pdf", "application/pdf")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		err         error
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `{"title":"report"}`,
+			err:         nil,
+		},
+		{
+			name:        "json without required field",
+			contentType: "application/json",
+			body:        `{}`,
+			err:         api.ErrEmptyRequireField,
+		},
+		{
+			name:        "multipart",
+			contentType: withFile,
+			body:        withFileBody,
+			err:         nil,
+		},
+		{
+			name:        "multipart with image matching wildcard",
+			contentType: withImage,
+			body:        withImageBody,
+			err:         nil,
+		},
+		{
+			name:        "multipart with not allowed file content type",
+			contentType: withText,
+			body:        withTextBody,
+			err: &api.PartContentTypeError{
+				Field:       "file",
+				ContentType: "text/plain",
+				Allowed:     "application/pdf, image/*",
+			},
+		},
+		{
+			name:        "multipart without file",
+			contentType: withoutFile,
+			body:        withoutFileBody,
+			err:         api.ErrEmptyRequireField,
+		},
+		{
+			name:        "multipart with json part",
+			contentType: withMeta,
+			body:        withMetaBody,
+			err:         nil,
+		},
+		{
+			name:        "multipart with invalid json part",
+			contentType: wrongMeta,
+			body:        wrongMetaBody,
+			err:         &api.FieldTypeError{Field: "meta.pages", Type: "integer"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:        "/documents",
+				Method:      http.MethodPost,
+				ContentType: tc.contentType,
+				Body:        io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPI_FindResponse_ReadWriteOnly(t *testing.T) {
+	a, err := parse.Parse("./testdata/read-write-only.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "read-only field is not required",
+			body: `{"name":"elon","password":"mars"}`,
+			err:  nil,
+		},
+		{
+			name: "read-only field is not validated",
+			body: `{"id":"42","name":"elon","password":"mars"}`,
+			err:  nil,
+		},
+		{
+			name: "write-only field is required",
+			body: `{"name":"elon"}`,
+			err:  api.ErrEmptyRequireField,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/users",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	got, err := a.FindResponse(api.FindResponseParams{
+		Path:   "/users/42",
+		Method: http.MethodGet,
+	})
+	require.NoError(t, err)
+
+	example, ok := got.ExampleValue("").(map[string]interface{})
+	require.True(t, ok)
+	require.Contains(t, example, "id")
+	require.Contains(t, example, "name")
+	require.NotContains(t, example, "password")
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"math"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// Bounds constrain range of numeric value, nil bound is not checked
+type Bounds struct {
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+}
+
+// newBounds returns bounds of numeric schema
+func newBounds(s openapi.Schema) Bounds {
+	return Bounds{
+		Minimum:          s.Minimum,
+		Maximum:          s.Maximum,
+		ExclusiveMinimum: s.ExclusiveMinimum,
+		ExclusiveMaximum: s.ExclusiveMaximum,
+	}
+}
+
+// violated returns name and value of bound violated by value, empty name is returned for value in range
+func (b Bounds) violated(value float64) (string, float64) {
+	switch {
+	case b.Minimum != nil && b.ExclusiveMinimum && value <= *b.Minimum:
+		return "exclusiveMinimum", *b.Minimum
+	case b.Minimum != nil && value < *b.Minimum:
+		return "minimum", *b.Minimum
+	case b.Maximum != nil && b.ExclusiveMaximum && value >= *b.Maximum:
+		return "exclusiveMaximum", *b.Maximum
+	case b.Maximum != nil && value > *b.Maximum:
+		return "maximum", *b.Maximum
+	default:
+		return "", 0
+	}
+}
+
+// rangeWidth is width of range of generated values missing one or both bounds
+const rangeWidth = 1000
+
+// generateInt returns value for integer schema without example within bounds of schema,
+// bounds of empty range result in its lower bound
+func (b *Builder) generateInt(bounds Bounds) int64 {
+	lo, hi := bounds.scaledRange(1)
+
+	return int64(lo + b.randIn(lo, hi))
+}
+
+// generateFloat returns value for number schema without example within bounds of schema, rounded to two decimals
+func (b *Builder) generateFloat(bounds Bounds) float64 {
+	const scale = 100
+
+	lo, hi := bounds.scaledRange(scale)
+
+	return (lo + b.randIn(lo, hi)) / scale
+}
+
+// randIn returns random offset of value from lo not exceeding hi
+func (b *Builder) randIn(lo, hi float64) float64 {
+	if hi <= lo {
+		return 0
+	}
+
+	span := hi - lo + 1
+	if span >= math.MaxInt64 {
+		span = math.MaxInt64
+	}
+
+	return float64(b.Rand.Int63n(int64(span)))
+}
+
+// scaledRange returns inclusive range of whole values multiplied by scale satisfying bounds,
+// missing maximum is set rangeWidth away from minimum, missing minimum is 0 or rangeWidth away from negative maximum
+func (b Bounds) scaledRange(scale float64) (float64, float64) {
+	var lo, hi float64
+
+	if b.Minimum != nil {
+		lo = math.Ceil(*b.Minimum * scale)
+		if b.ExclusiveMinimum && lo == *b.Minimum*scale {
+			lo++
+		}
+	}
+
+	if b.Maximum != nil {
+		hi = math.Floor(*b.Maximum * scale)
+		if b.ExclusiveMaximum && hi == *b.Maximum*scale {
+			hi--
+		}
+	}
+
+	switch {
+	case nil == b.Minimum && nil == b.Maximum:
+		hi = rangeWidth*scale - 1
+	case nil == b.Maximum:
+		hi = lo + rangeWidth*scale - 1
+	case nil == b.Minimum && hi < 0:
+		lo = hi - rangeWidth*scale + 1
+	}
+
+	return lo, hi
+}
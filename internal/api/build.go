@@ -1,14 +1,20 @@
 package api
 
 import (
-	"errors"
 	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
-
-	"github.com/neotoolkit/openapi"
+	"strings"
 
 	"github.com/neotoolkit/faker"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
 )
 
 // SchemaTypeError -.
@@ -20,9 +26,6 @@ func (e *SchemaTypeError) Error() string {
 	return "unknown type " + e.SchemaType
 }
 
-// ErrEmptyItems -.
-var ErrEmptyItems = errors.New("empty items in array")
-
 // ArrayExampleError -.
 type ArrayExampleError struct {
 	Data interface{}
@@ -40,9 +43,7 @@ func ParseArrayExample(data interface{}) ([]interface{}, error) {
 	d, ok := data.([]interface{})
 	if ok {
 		res := make([]interface{}, len(d))
-		for k, v := range d {
-			res[k] = v.(map[string]interface{})
-		}
+		copy(res, d)
 
 		return res, nil
 	}
@@ -73,6 +74,31 @@ func ParseObjectExample(data interface{}) (map[string]interface{}, error) {
 	return nil, &ObjectExampleError{Data: data}
 }
 
+// toInt64 returns integer example decoded by YAML or JSON decoder as int64, false is returned for example
+// out of range of int64, e.g. uint64 greater than math.MaxInt64
+func toInt64(data interface{}) (int64, bool) {
+	switch v := data.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(v), true
+	case float64:
+		if v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
 // RemoveTrailingSlash returns path without trailing slash
 func RemoveTrailingSlash(path string) string {
 	if len(path) > 0 && path[len(path)-1] == '/' {
@@ -82,43 +108,182 @@ func RemoveTrailingSlash(path string) string {
 	return path
 }
 
+// NormalizePath returns path without duplicate and trailing slashes, root path is `/`
+func NormalizePath(path string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i+1 < len(path) && path[i+1] == '/' {
+			continue
+		}
+
+		sb.WriteByte(path[i])
+	}
+
+	path = RemoveTrailingSlash(sb.String())
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
+
+// Builder -.
 type Builder struct {
 	OpenAPI    openapi.OpenAPI
 	Operations []Operation
 	Faker      faker.Faker
+	// Rand is used for example generation, examples are not generated when nil
+	Rand *rand.Rand
+	// Clock is used for date and date-time generation, system clock is used when nil
+	Clock Clock
+	// FS is used for external reference resolution, only local references are resolved when nil
+	FS fs.FS
+	// Base is path of specification in FS or its URL, external references are resolved relative to it
+	Base string
+	// Fetch returns content of document by URL of external reference, e.g. `https://example.com/schemas.yml#/User`,
+	// URL references are not resolved when nil
+	Fetch func(url string) ([]byte, error)
+	// BasePaths prefix paths of operations, paths of servers URLs with default variables are used when nil
+	BasePaths []string
+	// Strict checks response examples against their schemas, Build returns *ExampleError for mismatches
+	Strict bool
+
+	// documents caches external documents by path in FS or URL
+	documents map[string]interface{}
+	// refs contains references being converted, used for circular references detection
+	refs map[string]bool
+	// mismatches are response examples not matching their schemas found in strict mode
+	mismatches []ExampleMismatch
 }
 
 // Build -.
 func (b *Builder) Build() (API, error) {
-	for path, method := range b.OpenAPI.Paths {
-		if err := b.Add(path, http.MethodGet, method.Get); err != nil {
-			return API{}, err
+	// paths are sorted, so examples generated with same Rand seed are same
+	paths := make([]string, 0, len(b.OpenAPI.Paths))
+	for path := range b.OpenAPI.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	basePaths := b.basePaths()
+
+	for _, p := range paths {
+		for _, basePath := range basePaths {
+			if err := b.addPath(basePath+p, b.OpenAPI.Paths[p]); err != nil {
+				return API{}, err
+			}
 		}
+	}
+
+	if len(b.mismatches) > 0 {
+		return API{}, &ExampleError{Mismatches: b.mismatches}
+	}
 
-		if err := b.Add(path, http.MethodPost, method.Post); err != nil {
-			return API{}, err
+	return API{
+		Operations: b.Operations,
+		Clock:      b.Clock,
+		router:     newRouter(b.Operations),
+	}, nil
+}
+
+// basePaths returns distinct prefixes of operation paths, empty prefix is returned when there are none
+func (b *Builder) basePaths() []string {
+	basePaths := b.BasePaths
+	if nil == basePaths {
+		for _, server := range b.OpenAPI.Servers {
+			u, err := url.Parse(server.Expand())
+			if err != nil {
+				continue
+			}
+
+			basePaths = append(basePaths, u.Path)
 		}
+	}
 
-		if err := b.Add(path, http.MethodPut, method.Put); err != nil {
-			return API{}, err
+	res := make([]string, 0, len(basePaths))
+
+	for _, p := range basePaths {
+		p = strings.TrimSuffix(NormalizePath("/"+p), "/")
+		if !contains(res, p) {
+			res = append(res, p)
 		}
+	}
+
+	if len(res) == 0 {
+		return []string{""}
+	}
+
+	return res
+}
+
+// addPath adds operations of path
+func (b *Builder) addPath(path string, method *openapi.Path) error {
+	if err := b.Add(path, http.MethodGet, withParameters(method.Get, method.Parameters)); err != nil {
+		return err
+	}
+
+	if err := b.Add(path, http.MethodPost, withParameters(method.Post, method.Parameters)); err != nil {
+		return err
+	}
+
+	if err := b.Add(path, http.MethodPut, withParameters(method.Put, method.Parameters)); err != nil {
+		return err
+	}
+
+	if err := b.Add(path, http.MethodPatch, withParameters(method.Patch, method.Parameters)); err != nil {
+		return err
+	}
+
+	if err := b.Add(path, http.MethodDelete, withParameters(method.Delete, method.Parameters)); err != nil {
+		return err
+	}
+
+	if err := b.Add(path, http.MethodHead, withParameters(method.Head, method.Parameters)); err != nil {
+		return err
+	}
+
+	if err := b.Add(path, http.MethodOptions, withParameters(method.Options, method.Parameters)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// withParameters returns operation with path-level parameters not overridden by operation parameters
+// of same name and location
+func withParameters(o *openapi.Operation, params openapi.Parameters) *openapi.Operation {
+	if nil == o || len(params) == 0 {
+		return o
+	}
+
+	res := *o
+	res.Parameters = append(openapi.Parameters{}, o.Parameters...)
 
-		if err := b.Add(path, http.MethodPatch, method.Patch); err != nil {
-			return API{}, err
+	for _, p := range params {
+		overridden := false
+
+		for _, op := range o.Parameters {
+			if op.Name == p.Name && op.In == p.In {
+				overridden = true
+
+				break
+			}
 		}
 
-		if err := b.Add(path, http.MethodDelete, method.Delete); err != nil {
-			return API{}, err
+		if !overridden {
+			res.Parameters = append(res.Parameters, p)
 		}
 	}
 
-	return API{Operations: b.Operations}, nil
+	return &res
 }
 
 // Add -.
 func (b *Builder) Add(path, method string, o *openapi.Operation) error {
 	if o != nil {
-		p := RemoveTrailingSlash(path)
+		p := NormalizePath(path)
 
 		operation, err := b.Set(p, method, o)
 		if err != nil {
@@ -133,157 +298,1362 @@ func (b *Builder) Add(path, method string, o *openapi.Operation) error {
 
 // Set -.
 func (b *Builder) Set(path, method string, o *openapi.Operation) (Operation, error) {
+	path, query, err := splitQuery(path)
+	if err != nil {
+		return Operation{}, err
+	}
+
 	operation := Operation{
 		Method: method,
 		Path:   path,
+		Query:  query,
 	}
 
 	if nil == o {
 		return operation, nil
 	}
 
-	body, ok := o.RequestBody.Content["application/json"]
-	if ok {
-		var s openapi.Schema
+	operation.PathParams, err = b.pathParams(o.Parameters)
+	if err != nil {
+		return Operation{}, err
+	}
 
-		if body.Schema.Ref != "" {
-			schema, err := b.OpenAPI.LookupByReference(body.Schema.Ref)
-			if err != nil {
-				return Operation{}, fmt.Errorf("resolve reference: %w", err)
-			}
+	operation.QueryParams, err = b.parameters(o.Parameters, "query")
+	if err != nil {
+		return Operation{}, err
+	}
+
+	operation.HeaderParams, err = b.parameters(o.Parameters, "header")
+	if err != nil {
+		return Operation{}, err
+	}
+
+	operation.CookieParams, err = b.parameters(o.Parameters, "cookie")
+	if err != nil {
+		return Operation{}, err
+	}
+
+	for _, p := range o.Parameters {
+		if p.In != "query" || !p.Required {
+			continue
+		}
+
+		if _, ok := operation.Query[p.Name]; ok {
+			continue
+		}
+
+		if nil == operation.Query {
+			operation.Query = make(map[string]string)
+		}
+
+		operation.Query[p.Name] = ""
+	}
 
-			s = schema
-		} else {
-			s = body.Schema
+	if b.Strict {
+		if err := b.checkRequestExamples(method, path, o); err != nil {
+			return Operation{}, err
+		}
+	}
+
+	operation.Security, err = b.security(o)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	if o.CORS != nil {
+		operation.CORS = &CORS{
+			Origins: o.CORS.Origins,
+			Methods: o.CORS.Methods,
+			Headers: o.CORS.Headers,
 		}
+	}
 
-		operation.Body = make(map[string]FieldType, len(s.Properties))
+	operation.RateLimit, err = rateLimit(o.RateLimit)
+	if err != nil {
+		return Operation{}, err
+	}
 
-		for _, v := range s.Required {
-			operation.Body[v] = FieldType{
-				Required: true,
+	for _, mediaType := range []string{MediaTypeJSON, MediaTypeForm, MediaTypeMultipart} {
+		if body, ok := o.RequestBody.Content[mediaType]; ok {
+			if err := b.setBody(&operation, body.Schema); err != nil {
+				return Operation{}, err
 			}
+
+			operation.BodyEncoding = bodyEncoding(body)
+
+			break
 		}
+	}
+
+	if len(o.RequestBody.Content) > 1 {
+		operation.BodyMediaTypes = make(map[string]Operation, len(o.RequestBody.Content))
 
-		for k, v := range s.Properties {
-			operation.Body[k] = FieldType{
-				Required: operation.Body[k].Required,
-				Type:     v.Type,
+		for _, mediaType := range sortedMediaTypes(o.RequestBody.Content) {
+			body := o.RequestBody.Content[mediaType]
+
+			var rules Operation
+			if err := b.setBody(&rules, body.Schema); err != nil {
+				return Operation{}, err
 			}
+
+			rules.BodyEncoding = bodyEncoding(body)
+
+			operation.BodyMediaTypes[mediaType] = rules
 		}
 	}
 
-	for code, resp := range o.Responses {
-		statusCode, err := strconv.Atoi(code)
+	codes, err := sortedCodes(o.Responses)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	for _, code := range codes {
+		resp := o.Responses[code]
+
+		statusCode := http.StatusOK
+		class := codeRange(code)
+
+		switch {
+		case class > 0:
+			statusCode = class * 100
+		case code != defaultCode:
+			statusCode, _ = strconv.Atoi(code)
+		}
+
+		headers, err := b.headers(resp.Headers)
 		if err != nil {
 			return Operation{}, err
 		}
 
-		content, ok := resp.Content["application/json"]
-		if !ok {
+		if len(resp.Content) == 0 {
 			operation.Responses = append(operation.Responses, Response{
 				StatusCode: statusCode,
+				Default:    code == defaultCode,
+				Range:      class,
+				Headers:    headers,
 			})
 
 			continue
 		}
 
-		example := openapi.ExampleToResponse(content.Example)
+		for _, mediaType := range sortedMediaTypes(resp.Content) {
+			response, err := b.response(statusCode, mediaType, resp.Content[mediaType])
+			if err != nil {
+				return Operation{}, err
+			}
 
-		examples := make(map[string]interface{}, len(content.Examples)+1)
+			if b.Strict && (mediaType == MediaTypeJSON || IsXML(mediaType)) {
+				content := resp.Content[mediaType]
 
-		if len(content.Examples) > 0 {
-			for key, e := range content.Examples {
-				examples[key] = openapi.ExampleToResponse(e.Value)
+				at := ExampleMismatch{Method: method, Path: path, StatusCode: statusCode}
+				if err := b.checkExamples(at, content.Example, content.Examples, content.Schema); err != nil {
+					return Operation{}, err
+				}
 			}
 
-			examples[""] = openapi.ExampleToResponse(content.Examples[content.Examples.GetKeys()[0]].Value)
+			response.Default = code == defaultCode
+			response.Range = class
+			response.Headers = headers
+
+			operation.Responses = append(operation.Responses, response)
 		}
+	}
 
-		schema, err := b.convertSchema(content.Schema)
-		if err != nil {
-			return Operation{}, err
+	return operation, nil
+}
+
+// bodyEncoding returns allowed content types of multipart parts declared by encoding of media type
+func bodyEncoding(mediaType *openapi.MediaType) map[string]string {
+	var res map[string]string
+
+	for name, encoding := range mediaType.Encoding {
+		if encoding.ContentType == "" {
+			continue
 		}
 
-		operation.Responses = append(operation.Responses, Response{
-			StatusCode: statusCode,
-			MediaType:  "application/json",
-			Schema:     schema,
-			Example:    example,
-			Examples:   examples,
-		})
+		if nil == res {
+			res = make(map[string]string, len(mediaType.Encoding))
+		}
+
+		res[name] = encoding.ContentType
 	}
 
-	return operation, nil
+	return res
 }
 
-func (b *Builder) convertSchema(s openapi.Schema) (Schema, error) {
-	if s.Ref != "" {
-		schema, err := b.OpenAPI.LookupByReference(s.Ref)
+// setBody sets request body validation rules of operation from body schema
+func (b *Builder) setBody(operation *Operation, schema openapi.Schema) error {
+	s, err := b.resolve(schema)
+	if err != nil {
+		return err
+	}
+
+	operation.BodyMinProperties = s.MinProperties
+	operation.BodyMaxProperties = s.MaxProperties
+	operation.Body = make(map[string]FieldType, len(s.Properties))
+
+	for _, v := range s.Required {
+		operation.Body[v] = FieldType{
+			Required: true,
+		}
+	}
+
+	for k, v := range s.Properties {
+		// read-only property is neither required nor validated, though client may send it back
+		if v.ReadOnly {
+			operation.Body[k] = FieldType{}
+
+			continue
+		}
+
+		field, err := b.fieldType(*v)
 		if err != nil {
-			return nil, fmt.Errorf("resolve reference: %w", err)
+			return err
 		}
 
-		s = schema
+		field.Required = operation.Body[k].Required
+		operation.Body[k] = field
 	}
 
-	if s.Faker != "" {
-		return FakerSchema{Example: b.Faker.ByName(s.Faker)}, nil
+	if additional := s.AdditionalProperties; additional != nil {
+		operation.BodyStrict = !additional.Allowed
+
+		if additional.Schema != nil {
+			field, err := b.fieldType(*additional.Schema)
+			if err != nil {
+				return err
+			}
+
+			operation.BodyAdditionalProperties = &field
+		}
 	}
 
-	switch s.Type {
-	case "boolean":
-		val, _ := s.Example.(bool)
-		return BooleanSchema{Example: val}, nil
-	case "integer":
-		val, _ := s.Example.(int64)
-		return IntSchema{Example: val}, nil
-	case "number":
-		val, _ := s.Example.(float64)
-		return FloatSchema{Example: val}, nil
-	case "string":
-		val, _ := s.Example.(string)
-		return StringSchema{Example: val}, nil
-	case "array":
-		if nil == s.Items {
-			return nil, ErrEmptyItems
+	return b.setBodyVariants(operation, s)
+}
+
+// setBodyVariants sets request body validation rules of oneOf and anyOf branches of body schema
+// by values of discriminator property, body without discriminator has no variants
+func (b *Builder) setBodyVariants(operation *Operation, s openapi.Schema) error {
+	branches := s.OneOf
+	if len(branches) == 0 {
+		branches = s.AnyOf
+	}
+
+	if nil == s.Discriminator || s.Discriminator.PropertyName == "" || len(branches) == 0 {
+		return nil
+	}
+
+	base := s
+	base.OneOf, base.AnyOf, base.Discriminator = nil, nil, nil
+
+	operation.BodyDiscriminator = &Discriminator{
+		Field:    s.Discriminator.PropertyName,
+		Variants: make(map[string]Operation, len(branches)),
+	}
+
+	for _, branch := range branches {
+		value := discriminatorValue(s.Discriminator, branch)
+		if value == "" {
+			continue
 		}
 
-		itemsSchema, err := b.convertSchema(*s.Items)
+		chosen, err := b.resolve(*branch)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		arrExample, err := ParseArrayExample(s.Example)
-		if err != nil {
-			return nil, err
+		var variant Operation
+		if err := b.setBody(&variant, mergeSchemas(base, chosen)); err != nil {
+			return err
 		}
 
-		return ArraySchema{
-			Type:    itemsSchema,
-			Example: arrExample,
-		}, nil
-	case "object":
-		obj := ObjectSchema{Properties: make(map[string]Schema, len(s.Properties))}
+		operation.BodyDiscriminator.Variants[value] = variant
+	}
+
+	return nil
+}
+
+// pathParams returns constraints of path parameters with schemas, e.g. integer type or pattern
+func (b *Builder) pathParams(params openapi.Parameters) (map[string]FieldType, error) {
+	fields, err := b.parameters(params, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]FieldType
+
+	for name, field := range fields {
+		// any string value of path segment is valid
+		if !constrained(field) {
+			continue
+		}
+
+		if nil == res {
+			res = make(map[string]FieldType)
+		}
+
+		res[name] = field
+	}
+
+	return res, nil
+}
+
+// parameters returns types of parameters of location, e.g. query, parameter without schema allows any value
+func (b *Builder) parameters(params openapi.Parameters, in string) (map[string]FieldType, error) {
+	var res map[string]FieldType
+
+	for _, p := range params {
+		if p.In != in {
+			continue
+		}
+
+		var field FieldType
 
-		for key, prop := range s.Properties {
-			propSchema, err := b.convertSchema(*prop)
+		if p.Schema != nil {
+			var err error
+
+			field, err = b.fieldType(*p.Schema)
 			if err != nil {
 				return nil, err
 			}
+		}
 
-			obj.Properties[key] = propSchema
+		field.Required = p.Required
+
+		if nil == res {
+			res = make(map[string]FieldType)
 		}
 
-		objExample, err := ParseObjectExample(s.Example)
+		res[p.Name] = field
+	}
+
+	return res, nil
+}
+
+// constrained reports whether field type restricts string values, e.g. by integer type or pattern
+func constrained(f FieldType) bool {
+	switch {
+	case f.Type != "" && f.Type != "string":
+		return true
+	case len(f.Enum) > 0, f.Pattern != nil, f.MinLength > 0, f.MaxLength != nil:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldType returns validation rules of schema with rules of properties of object and items of array,
+// recursive schema is validated down to its recursion
+func (b *Builder) fieldType(s openapi.Schema) (FieldType, error) {
+	leave, err := b.enter(s.Ref)
+	if err != nil {
+		return FieldType{}, nil
+	}
+	defer leave()
+
+	s, err = b.resolve(s)
+	if err != nil {
+		return FieldType{}, err
+	}
+
+	field := newFieldType(s)
+
+	if len(s.Properties) > 0 {
+		field.Properties = make(map[string]FieldType, len(s.Properties))
+
+		for name, p := range s.Properties {
+			// read-only property is neither required nor validated
+			if p.ReadOnly {
+				field.Properties[name] = FieldType{}
+
+				continue
+			}
+
+			prop, err := b.fieldType(*p)
+			if err != nil {
+				return FieldType{}, err
+			}
+
+			prop.Required = contains(s.Required, name)
+			field.Properties[name] = prop
+		}
+	}
+
+	if s.Items != nil {
+		items, err := b.fieldType(*s.Items)
 		if err != nil {
-			return nil, err
+			return FieldType{}, err
 		}
 
-		obj.Example = objExample
+		field.Items = &items
+	}
 
-		return obj, nil
-	default:
-		return nil, &SchemaTypeError{SchemaType: s.Type}
+	return field, nil
+}
+
+// newFieldType returns type of request body field with schema, pattern of schema is compiled once,
+// pattern not supported by regexp package, e.g. with lookahead, is not checked
+func newFieldType(s openapi.Schema) FieldType {
+	var pattern *regexp.Regexp
+
+	if s.Pattern != "" {
+		pattern, _ = regexp.Compile(s.Pattern)
+	}
+
+	return FieldType{
+		Type:      s.Type,
+		Enum:      s.Enum,
+		MinLength: s.MinLength,
+		MaxLength: s.MaxLength,
+		Pattern:   pattern,
+		Format:    s.Format,
+		Nullable:  s.Nullable,
+		Bounds:    newBounds(s),
+	}
+}
+
+// splitQuery returns path without query string and query parameters values from path,
+// e.g. `/search?type=user` declares operation for requests with `type=user` query parameter
+func splitQuery(path string) (string, map[string]string, error) {
+	i := strings.Index(path, "?")
+	if i < 0 {
+		return path, nil, nil
+	}
+
+	values, err := url.ParseQuery(path[i+1:])
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := make(map[string]string, len(values))
+	for k, v := range values {
+		query[k] = v[0]
+	}
+
+	return NormalizePath(path[:i]), query, nil
+}
+
+// defaultCode is response code of response for any status code not covered individually
+const defaultCode = "default"
+
+// codeRange returns class of status codes of range response code, e.g. 2 for `2XX`, zero for other codes
+func codeRange(code string) int {
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' || !strings.EqualFold(code[1:], "XX") {
+		return 0
 	}
+
+	return int(code[0] - '0')
+}
+
+// sortedCodes returns response codes sorted by numeric value, range of codes, e.g. `2XX`,
+// follows its codes and default response is last
+func sortedCodes(responses openapi.Responses) ([]string, error) {
+	codes := make([]string, 0, len(responses))
+	values := make(map[string]int, len(responses))
+
+	for code := range responses {
+		codes = append(codes, code)
+
+		if code == defaultCode {
+			continue
+		}
+
+		if class := codeRange(code); class > 0 {
+			values[code] = class*100 + 99
+
+			continue
+		}
+
+		value, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, err
+		}
+
+		values[code] = value
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i] == defaultCode {
+			return false
+		}
+
+		if codes[j] == defaultCode {
+			return true
+		}
+
+		return values[codes[i]] < values[codes[j]]
+	})
+
+	return codes, nil
+}
+
+// sortedMediaTypes returns media types of content sorted by name, JSON media type is first
+func sortedMediaTypes(content openapi.Content) []string {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		if mediaTypes[i] == MediaTypeJSON || mediaTypes[j] == MediaTypeJSON {
+			return mediaTypes[i] == MediaTypeJSON
+		}
+
+		return mediaTypes[i] < mediaTypes[j]
+	})
+
+	return mediaTypes
+}
+
+// response returns response of media type
+func (b *Builder) response(statusCode int, mediaType string, content *openapi.MediaType) (Response, error) {
+	switch {
+	case mediaType == MediaTypeJSON:
+		return b.schemaResponse(statusCode, mediaType, content)
+	case IsXML(mediaType):
+		// string example is XML document served as is
+		if _, ok := content.Example.(string); ok {
+			return b.textResponse(statusCode, mediaType, content)
+		}
+
+		return b.schemaResponse(statusCode, mediaType, content)
+	case mediaType == MediaTypeOctetStream:
+		return b.binaryResponse(statusCode, content)
+	default:
+		return b.textResponse(statusCode, mediaType, content)
+	}
+}
+
+// schemaResponse returns response of JSON or XML media type with body built from examples and schema
+func (b *Builder) schemaResponse(statusCode int, mediaType string, content *openapi.MediaType) (Response, error) {
+	example := openapi.ExampleToResponse(content.Example)
+
+	examples := make(map[string]interface{}, len(content.Examples)+1)
+
+	var weights map[string]float64
+
+	if len(content.Examples) > 0 {
+		for key, e := range content.Examples {
+			value, err := b.exampleValue(e)
+			if err != nil {
+				return Response{}, err
+			}
+
+			examples[key] = openapi.ExampleToResponse(value)
+
+			if e.Weight > 0 {
+				if nil == weights {
+					weights = make(map[string]float64, len(content.Examples))
+				}
+
+				weights[key] = e.Weight
+			}
+		}
+
+		// empty key duplicates the default example
+		examples[""] = examples[content.Examples.DefaultKey()]
+	}
+
+	schema, err := b.convertSchema(content.Schema)
+	if err != nil {
+		return Response{}, err
+	}
+
+	variants, err := b.variants(content.Schema)
+	if err != nil {
+		return Response{}, err
+	}
+
+	response := Response{
+		StatusCode:     statusCode,
+		MediaType:      mediaType,
+		Schema:         schema,
+		Example:        example,
+		Examples:       examples,
+		ExampleWeights: weights,
+		Variants:       variants,
+	}
+
+	if IsXML(mediaType) {
+		response.XMLName, err = b.xmlName(content.Schema)
+		if err != nil {
+			return Response{}, err
+		}
+	}
+
+	return response, nil
+}
+
+// variants returns schemas of oneOf or anyOf branches of schema by variant name, nil for schema without branches
+func (b *Builder) variants(s openapi.Schema) (map[string]Schema, error) {
+	s, err := b.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := s.OneOf
+	if len(branches) == 0 {
+		branches = s.AnyOf
+	}
+
+	extended := false
+	if len(branches) == 0 {
+		branches, extended = mappedBranches(s.Discriminator), true
+	}
+
+	if len(branches) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]Schema, len(branches))
+
+	for i, branch := range branches {
+		if nil == branch {
+			continue
+		}
+
+		variant := s
+		variant.OneOf, variant.AnyOf = []*openapi.Schema{branch}, nil
+
+		// base schema is narrowed to single mapped schema
+		if extended {
+			value := discriminatorValue(s.Discriminator, branch)
+
+			variant.OneOf = nil
+			variant.Discriminator = &openapi.Discriminator{
+				PropertyName: s.Discriminator.PropertyName,
+				Mapping:      map[string]string{value: s.Discriminator.Mapping[value]},
+			}
+		}
+
+		schema, err := b.convertSchema(variant)
+		if err != nil {
+			return nil, err
+		}
+
+		res[variantName(s.Discriminator, branch, i)] = schema
+	}
+
+	return res, nil
+}
+
+// variantName returns discriminator value of branch, name of referenced schema or index of branch
+func variantName(d *openapi.Discriminator, branch *openapi.Schema, i int) string {
+	if value := discriminatorValue(d, branch); value != "" {
+		return value
+	}
+
+	if branch.Ref != "" {
+		return branch.Ref[strings.LastIndex(branch.Ref, "/")+1:]
+	}
+
+	return strconv.Itoa(i)
+}
+
+// headers returns response headers sorted by name. Content-Type header is ignored, as OpenAPI requires.
+func (b *Builder) headers(headers openapi.Headers) ([]Header, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	res := make([]Header, 0, len(names))
+
+	for _, name := range names {
+		h := headers[name]
+		if nil == h || strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		example, err := b.defaultExample(h.Example, h.Examples)
+		if err != nil {
+			return nil, err
+		}
+
+		header := Header{
+			Name:    name,
+			Example: example,
+		}
+
+		if h.Schema != nil {
+			schema, err := b.convertSchema(*h.Schema)
+			if err != nil {
+				return nil, err
+			}
+
+			header.Schema = schema
+		}
+
+		res = append(res, header)
+	}
+
+	return res, nil
+}
+
+// xmlName returns name of XML element of schema: `xml.name`, name of referenced schema or `response`
+func (b *Builder) xmlName(s openapi.Schema) (string, error) {
+	resolved, err := b.resolve(s)
+	if err != nil {
+		return "", err
+	}
+
+	if resolved.XML != nil && resolved.XML.Name != "" {
+		return resolved.XML.Name, nil
+	}
+
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:], nil
+	}
+
+	return "response", nil
+}
+
+// xmlItemName returns `xml.name` of array items, empty name is returned when items have no one
+func (b *Builder) xmlItemName(items openapi.Schema) string {
+	if items.XML == nil && items.Ref != "" {
+		resolved, err := b.resolve(items)
+		if err != nil {
+			return ""
+		}
+
+		items = resolved
+	}
+
+	if nil == items.XML {
+		return ""
+	}
+
+	return items.XML.Name
+}
+
+// textResponse returns response of media type other than JSON and XML with body taken from string example as is.
+// Body of response without string example is example of scalar schema, e.g. generated string or number.
+func (b *Builder) textResponse(statusCode int, mediaType string, content *openapi.MediaType) (Response, error) {
+	value, err := b.defaultExample(content.Example, content.Examples)
+	if err != nil {
+		return Response{}, err
+	}
+
+	example, ok := value.(string)
+	if !ok {
+		example, err = b.textExample(content.Schema)
+		if err != nil {
+			return Response{}, err
+		}
+	}
+
+	return Response{
+		StatusCode: statusCode,
+		MediaType:  mediaType,
+		Schema:     StringSchema{Example: example},
+	}, nil
+}
+
+// textExample returns text of example of scalar schema, empty text is returned for schema of objects and arrays
+func (b *Builder) textExample(s openapi.Schema) (string, error) {
+	if example, ok := s.Example.(string); ok {
+		return example, nil
+	}
+
+	if s.Ref == "" && s.Type == "" {
+		return "", nil
+	}
+
+	schema, err := b.convertSchema(s)
+	if err != nil {
+		return "", err
+	}
+
+	switch schema.(type) {
+	case ObjectSchema, ArraySchema, NullSchema:
+		return "", nil
+	}
+
+	example := schema.ExampleValue()
+	if nil == example {
+		return "", nil
+	}
+
+	return fmt.Sprint(example), nil
+}
+
+// binaryResponse returns response with known-size body taken from string example
+func (b *Builder) binaryResponse(statusCode int, content *openapi.MediaType) (Response, error) {
+	value, err := b.defaultExample(content.Example, content.Examples)
+	if err != nil {
+		return Response{}, err
+	}
+
+	example, ok := value.(string)
+	if !ok {
+		example, _ = content.Schema.Example.(string)
+	}
+
+	return Response{
+		StatusCode: statusCode,
+		MediaType:  MediaTypeOctetStream,
+		Schema:     BinarySchema{Example: []byte(example)},
+	}, nil
+}
+
+// resolve returns schema with resolved reference and merged allOf members
+func (b *Builder) resolve(s openapi.Schema) (openapi.Schema, error) {
+	// chain of references, e.g. external file containing reference only
+	visited := make(map[string]bool)
+
+	for s.Ref != "" {
+		ref := b.canonical(s.Ref)
+		if visited[ref] {
+			return openapi.Schema{}, &CircularReferenceError{Ref: ref}
+		}
+
+		visited[ref] = true
+
+		schema, err := b.lookup(s.Ref)
+		if err != nil {
+			return openapi.Schema{}, fmt.Errorf("resolve reference: %w", err)
+		}
+
+		s = schema
+	}
+
+	if len(s.AllOf) == 0 {
+		return s, nil
+	}
+
+	members := s.AllOf
+	s.AllOf = nil
+
+	for _, m := range members {
+		if nil == m {
+			continue
+		}
+
+		leave, err := b.enter(m.Ref)
+		if err != nil {
+			return openapi.Schema{}, err
+		}
+
+		member, err := b.resolve(*m)
+
+		leave()
+
+		if err != nil {
+			return openapi.Schema{}, err
+		}
+
+		s = mergeSchemas(s, member)
+	}
+
+	if s.Type == "" {
+		s.Type = "object"
+	}
+
+	return s, nil
+}
+
+// branch returns schema merged with representative branch of oneOf or anyOf. Branch is chosen once
+// on build, randomly when Rand is set, otherwise first one, so response shape is stable per operation.
+func (b *Builder) branch(s openapi.Schema) (openapi.Schema, error) {
+	branches := s.OneOf
+	if len(branches) == 0 {
+		branches = s.AnyOf
+	}
+
+	s.OneOf, s.AnyOf = nil, nil
+
+	// base schema without branches is extended by schemas of discriminator mapping
+	extended := false
+	if len(branches) == 0 {
+		branches, extended = mappedBranches(s.Discriminator), true
+	}
+
+	if len(branches) == 0 {
+		return s, nil
+	}
+
+	i := 0
+	if b.Rand != nil && len(branches) > 1 {
+		i = b.Rand.Intn(len(branches))
+	}
+
+	if nil == branches[i] {
+		return s, nil
+	}
+
+	leave, err := b.enter(branches[i].Ref)
+	if err != nil {
+		return openapi.Schema{}, err
+	}
+	defer leave()
+
+	resolve := b.resolve
+	if extended {
+		resolve = b.extension
+	}
+
+	chosen, err := resolve(*branches[i])
+	if err != nil {
+		return openapi.Schema{}, err
+	}
+
+	chosen, err = b.branch(chosen)
+	if err != nil {
+		return openapi.Schema{}, err
+	}
+
+	s = mergeSchemas(s, chosen)
+
+	if s.Type == "" {
+		s.Type = "object"
+	}
+
+	// example of discriminator property identifies chosen branch
+	if value := discriminatorValue(s.Discriminator, branches[i]); value != "" {
+		s = withPropertyExample(s, s.Discriminator.PropertyName, value)
+	}
+
+	return s, nil
+}
+
+// mappedBranches returns references of discriminator mapping sorted by discriminator value,
+// mapping value which is not reference is name of component schema
+func mappedBranches(d *openapi.Discriminator) []*openapi.Schema {
+	if nil == d || len(d.Mapping) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(d.Mapping))
+	for value := range d.Mapping {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	res := make([]*openapi.Schema, len(values))
+
+	for i, value := range values {
+		ref := d.Mapping[value]
+		if !strings.ContainsAny(ref, "#/.") {
+			ref = "#/components/schemas/" + ref
+		}
+
+		res[i] = &openapi.Schema{Ref: ref}
+	}
+
+	return res
+}
+
+// extension returns resolved schema extending base schema by allOf. Members of allOf being converted,
+// i.e. base schema itself, are skipped, since their properties are already merged.
+func (b *Builder) extension(s openapi.Schema) (openapi.Schema, error) {
+	for s.Ref != "" {
+		schema, err := b.lookup(s.Ref)
+		if err != nil {
+			return openapi.Schema{}, fmt.Errorf("resolve reference: %w", err)
+		}
+
+		s = schema
+	}
+
+	members := make([]*openapi.Schema, 0, len(s.AllOf))
+
+	for _, m := range s.AllOf {
+		if m != nil && m.Ref != "" && b.refs[b.canonical(m.Ref)] {
+			continue
+		}
+
+		members = append(members, m)
+	}
+
+	s.AllOf = members
+
+	return b.resolve(s)
+}
+
+// discriminatorValue returns value of discriminator property identifying branch: key of mapping
+// to reference of branch or name of referenced schema. Empty value is returned without discriminator.
+func discriminatorValue(d *openapi.Discriminator, branch *openapi.Schema) string {
+	if nil == d || d.PropertyName == "" || nil == branch || branch.Ref == "" {
+		return ""
+	}
+
+	name := branch.Ref[strings.LastIndex(branch.Ref, "/")+1:]
+
+	values := make([]string, 0, len(d.Mapping))
+	for value := range d.Mapping {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	for _, value := range values {
+		if ref := d.Mapping[value]; ref == branch.Ref || ref == name {
+			return value
+		}
+	}
+
+	return name
+}
+
+// withPropertyExample returns schema with example of property, properties of schema are copied
+func withPropertyExample(s openapi.Schema, name string, example interface{}) openapi.Schema {
+	prop := openapi.Schema{Type: "string"}
+	if p := s.Properties[name]; p != nil {
+		prop = *p
+	}
+
+	prop.Example = example
+
+	properties := make(openapi.Schemas, len(s.Properties)+1)
+	for k, v := range s.Properties {
+		properties[k] = v
+	}
+
+	properties[name] = &prop
+	s.Properties = properties
+
+	return s
+}
+
+// mergeSchemas merges src into dst. Properties of src overwrite properties of dst with same name,
+// constraint-only members narrow constraints of merged schema.
+func mergeSchemas(dst, src openapi.Schema) openapi.Schema {
+	if src.Type != "" {
+		dst.Type = src.Type
+	}
+
+	if src.Format != "" {
+		dst.Format = src.Format
+	}
+
+	if src.Faker != "" {
+		dst.Faker = src.Faker
+	}
+
+	if src.Items != nil {
+		dst.Items = src.Items
+	}
+
+	if src.Default != nil {
+		dst.Default = src.Default
+	}
+
+	if src.Example != nil {
+		dst.Example = mergeExamples(dst.Example, src.Example)
+	}
+
+	if len(src.Properties) > 0 {
+		properties := make(openapi.Schemas, len(dst.Properties)+len(src.Properties))
+
+		for k, v := range dst.Properties {
+			properties[k] = v
+		}
+
+		for k, v := range src.Properties {
+			properties[k] = v
+		}
+
+		dst.Properties = properties
+	}
+
+	if len(src.Required) > 0 {
+		required := make([]string, 0, len(dst.Required)+len(src.Required))
+		required = append(required, dst.Required...)
+
+		for _, r := range src.Required {
+			if !contains(required, r) {
+				required = append(required, r)
+			}
+		}
+
+		dst.Required = required
+	}
+
+	if src.MinProperties > dst.MinProperties {
+		dst.MinProperties = src.MinProperties
+	}
+
+	if src.MaxProperties > 0 && (dst.MaxProperties == 0 || src.MaxProperties < dst.MaxProperties) {
+		dst.MaxProperties = src.MaxProperties
+	}
+
+	return dst
+}
+
+// mergeExamples returns object example with properties of both examples, properties of src win.
+// Example which is not object is replaced by src.
+func mergeExamples(dst, src interface{}) interface{} {
+	d, ok := dst.(map[string]interface{})
+	if !ok {
+		return src
+	}
+
+	s, ok := src.(map[string]interface{})
+	if !ok {
+		return src
+	}
+
+	res := make(map[string]interface{}, len(d)+len(s))
+
+	for k, v := range d {
+		res[k] = v
+	}
+
+	for k, v := range s {
+		res[k] = v
+	}
+
+	return res
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Builder) convertSchema(s openapi.Schema) (Schema, error) {
+	leave, err := b.enter(s.Ref)
+	if err != nil {
+		// recursive schema, e.g. tree node with children of same type, is expanded once
+		return ObjectSchema{}, nil
+	}
+	defer leave()
+
+	s, err = b.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err = b.branch(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// default is example of schema without one, it takes precedence over faker
+	if nil == s.Example && s.Default != nil {
+		s.Example, s.Faker = s.Default, ""
+	}
+
+	if s.Faker != "" {
+		return FakerSchema{Example: b.faker().ByName(s.Faker)}, nil
+	}
+
+	if nil == s.Example && len(s.Enum) > 0 {
+		s.Example = b.pickEnum(s.Enum)
+	}
+
+	// examples are generated only when not specified, explicit example always wins
+	generate := nil == s.Example && b.Rand != nil
+
+	if generate && b.generateNull(s) {
+		return NullSchema{}, nil
+	}
+
+	switch s.Type {
+	case "boolean":
+		val, _ := s.Example.(bool)
+		if generate {
+			val = b.generateBool()
+		}
+
+		return BooleanSchema{Example: val, Nullable: s.Nullable}, nil
+	case "integer":
+		val, _ := toInt64(s.Example)
+		if generate {
+			val = b.generateInt(newBounds(s))
+		}
+
+		// example above int64 range is kept as is instead of wrapping around
+		unsigned, _ := s.Example.(uint64)
+		if unsigned <= math.MaxInt64 {
+			unsigned = 0
+		}
+
+		return IntSchema{Example: val, Unsigned: unsigned, Nullable: s.Nullable, Bounds: newBounds(s)}, nil
+	case "number":
+		val, _ := s.Example.(float64)
+		if generate {
+			val = b.generateFloat(newBounds(s))
+		}
+
+		return FloatSchema{Example: val, Nullable: s.Nullable, Bounds: newBounds(s)}, nil
+	case "string":
+		val, _ := s.Example.(string)
+		if generate {
+			val = b.generateString(s)
+		}
+
+		return StringSchema{Example: val, Nullable: s.Nullable}, nil
+	case "null":
+		return NullSchema{}, nil
+	case "array":
+		if nil == s.Example && len(s.PrefixItems) > 0 {
+			return b.tupleSchema(s)
+		}
+
+		arrExample, err := ParseArrayExample(s.Example)
+		if err != nil {
+			return nil, err
+		}
+
+		// array without items is generated as empty array
+		if nil == s.Items {
+			return ArraySchema{Example: arrExample, Nullable: s.Nullable}, nil
+		}
+
+		itemsSchema, err := b.convertSchema(*s.Items)
+		if err != nil {
+			return nil, err
+		}
+
+		arr := ArraySchema{
+			Type:        itemsSchema,
+			Example:     arrExample,
+			Nullable:    s.Nullable,
+			XMLItemName: b.xmlItemName(*s.Items),
+			XMLWrapped:  s.XML != nil && s.XML.Wrapped,
+		}
+
+		if generate {
+			items, err := b.generateItems(s, itemsSchema)
+			if err != nil {
+				return nil, err
+			}
+
+			if items.Count > len(items.Pool) {
+				arr.Generated = &items
+			} else {
+				arr.Example = items.Pool
+			}
+		}
+
+		return arr, nil
+	case "object":
+		obj := ObjectSchema{
+			Properties: make(map[string]Schema, len(s.Properties)),
+			Nullable:   s.Nullable,
+		}
+
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			// write-only property is not sent in responses
+			if s.Properties[key].WriteOnly {
+				continue
+			}
+
+			propSchema, err := b.convertProperty(key, *s.Properties[key])
+			if err != nil {
+				return nil, err
+			}
+
+			obj.Properties[key] = propSchema
+
+			if xml := s.Properties[key].XML; xml != nil && xml.Name != "" {
+				if nil == obj.XMLNames {
+					obj.XMLNames = make(map[string]string)
+				}
+
+				obj.XMLNames[key] = xml.Name
+			}
+
+			if xml := s.Properties[key].XML; xml != nil && xml.Attribute {
+				if nil == obj.XMLAttributes {
+					obj.XMLAttributes = make(map[string]bool)
+				}
+
+				obj.XMLAttributes[key] = true
+			}
+		}
+
+		if additional := s.AdditionalProperties; additional != nil && additional.Allowed {
+			// values of free-form object without schema are strings
+			valueSchema := openapi.Schema{Type: "string"}
+			if additional.Schema != nil {
+				valueSchema = *additional.Schema
+			}
+
+			obj.AdditionalProperties, err = b.convertSchema(valueSchema)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		objExample, err := ParseObjectExample(s.Example)
+		if err != nil {
+			return nil, err
+		}
+
+		obj.Example = objExample
+
+		return obj, nil
+	default:
+		return nil, &SchemaTypeError{SchemaType: s.Type}
+	}
+}
+
+// tupleSchema converts array of OpenAPI 3.1 `prefixItems`, example has item of each schema by position
+func (b *Builder) tupleSchema(s openapi.Schema) (Schema, error) {
+	example := make([]interface{}, len(s.PrefixItems))
+
+	for i, item := range s.PrefixItems {
+		itemSchema, err := b.convertSchema(*item)
+		if err != nil {
+			return nil, err
+		}
+
+		example[i] = itemSchema.ExampleValue()
+	}
+
+	return ArraySchema{Example: example, Nullable: s.Nullable}, nil
+}
+
+// convertProperty converts object property schema. String properties without example and format
+// get value inferred from property name, e.g. `email`, at any nesting level.
+func (b *Builder) convertProperty(name string, s openapi.Schema) (Schema, error) {
+	leave, err := b.enter(s.Ref)
+	if err != nil {
+		// recursive schema, e.g. tree node with children of same type, is expanded once
+		return ObjectSchema{}, nil
+	}
+	defer leave()
+
+	s, err = b.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Type != "string" || s.Example != nil || s.Default != nil || len(s.Enum) > 0 || s.Format != "" || s.Pattern != "" || s.Faker != "" || nil == b.Rand {
+		return b.convertSchema(s)
+	}
+
+	generate, ok := inferByName(name)
+	if !ok {
+		return b.convertSchema(s)
+	}
+
+	if b.generateNull(s) {
+		return NullSchema{}, nil
+	}
+
+	val, _ := generate(b.faker()).(string)
+
+	return StringSchema{Example: fitLength(val, s), Nullable: s.Nullable}, nil
 }
@@ -2,13 +2,21 @@ package api_test
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"testing"
+	"testing/fstest"
 
-	"github.com/neotoolkit/openapi"
 	"github.com/stretchr/testify/require"
 
 	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/openapi"
+	"github.com/neotoolkit/dummy/internal/parse"
 )
 
 func TestSchemaTypeError(t *testing.T) {
@@ -54,6 +62,18 @@ func TestParseArrayExample(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "array of strings",
+			data: []interface{}{"a", "b", "c"},
+			want: []interface{}{"a", "b", "c"},
+			err:  nil,
+		},
+		{
+			name: "array of integers",
+			data: []interface{}{uint64(1), uint64(2), uint64(3)},
+			want: []interface{}{uint64(1), uint64(2), uint64(3)},
+			err:  nil,
+		},
 		{
 			name: "not array",
 			data: "string",
@@ -153,6 +173,48 @@ func TestRemoveTrailingSlash(t *testing.T) {
 	}
 }
 
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "empty path",
+			path: "",
+			want: "/",
+		},
+		{
+			name: "root",
+			path: "/",
+			want: "/",
+		},
+		{
+			name: "root with duplicate slashes",
+			path: "///",
+			want: "/",
+		},
+		{
+			name: "trailing slash",
+			path: "/users/",
+			want: "/users",
+		},
+		{
+			name: "duplicate slashes",
+			path: "//users//{id}//",
+			want: "/users/{id}",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := api.NormalizePath(tc.path)
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestBuilder_Build(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -408,7 +470,7 @@ func TestBuilder_Build(t *testing.T) {
 				require.EqualError(t, err, tc.err.Error())
 			}
 
-			require.Equal(t, tc.want, res)
+			require.Equal(t, tc.want.Operations, res.Operations)
 		})
 	}
 }
@@ -557,3 +619,744 @@ func TestBuilder_Set(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_Set_ExampleWeights(t *testing.T) {
+	a, err := parse.Parse("./testdata/weighted-examples.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+	require.Len(t, a.Operations[0].Responses, 1)
+
+	require.Equal(t, map[string]float64{"ok": 3, "degraded": 1}, a.Operations[0].Responses[0].ExampleWeights)
+}
+
+func TestBuilder_Set_DefaultExample(t *testing.T) {
+	examples := openapi.Examples{
+		"c": {Value: map[string]interface{}{"name": "c"}},
+		"a": {Value: map[string]interface{}{"name": "a"}},
+		"b": {Value: map[string]interface{}{"name": "b"}},
+	}
+
+	operation := &openapi.Operation{
+		Responses: openapi.Responses{"200": &openapi.Response{
+			Content: openapi.Content{"application/json": &openapi.MediaType{
+				Schema:   openapi.Schema{Type: "object"},
+				Examples: examples,
+			}},
+		}},
+	}
+
+	for i := 0; i < 10; i++ {
+		got, err := (&api.Builder{}).Set("/users", http.MethodGet, operation)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"name": "a"}, got.Responses[0].ExampleValue(""))
+	}
+
+	examples["b"] = openapi.Example{Value: map[string]interface{}{"name": "b"}, Default: true}
+
+	got, err := (&api.Builder{}).Set("/users", http.MethodGet, operation)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "b"}, got.Responses[0].ExampleValue(""))
+
+	named := openapi.Examples{
+		"text":    {Value: "plain"},
+		"default": {Value: "flagged", Default: true},
+	}
+
+	operation = &openapi.Operation{
+		Responses: openapi.Responses{"200": &openapi.Response{
+			Headers: openapi.Headers{"X-Request-Id": &openapi.Header{Examples: named}},
+			Content: openapi.Content{"text/plain": &openapi.MediaType{
+				Schema:   openapi.Schema{Type: "string"},
+				Examples: named,
+			}},
+		}},
+	}
+
+	got, err = (&api.Builder{}).Set("/users", http.MethodGet, operation)
+	require.NoError(t, err)
+	require.Equal(t, "flagged", got.Responses[0].ExampleValue(""))
+	require.Equal(t, "flagged", got.Responses[0].Headers[0].Value())
+}
+
+func TestBuilder_Set_AllOfConstraint(t *testing.T) {
+	a, err := parse.Parse("./testdata/all-of.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	operation := a.Operations[0]
+
+	require.Equal(t, map[string]api.FieldType{
+		"id":   {Required: true, Type: "string"},
+		"name": {Required: false, Type: "string"},
+	}, operation.Body)
+	require.Equal(t, 2, operation.BodyMinProperties)
+	require.Equal(t, 0, operation.BodyMaxProperties)
+
+	require.Equal(t, map[string]interface{}{
+		"id":   "e1afccea-5168-4735-84d4-cb96f6fb5d25",
+		"name": "Elon",
+	}, operation.Responses[0].ExampleValue(""))
+
+	_, err = a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: http.MethodPost,
+		Body:   io.NopCloser(strings.NewReader(`{"id": "1"}`)),
+	})
+	require.EqualError(t, err, (&api.PropertiesCountError{Count: 1, Min: 2}).Error())
+
+	_, err = a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: http.MethodPost,
+		Body:   io.NopCloser(strings.NewReader(`{"id": "1", "name": "Elon"}`)),
+	})
+	require.NoError(t, err)
+}
+
+func TestBuilder_Build_InferByNameRecursively(t *testing.T) {
+	a, err := parse.Parse("./testdata/nested.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	got, ok := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+	require.True(t, ok)
+	require.Contains(t, got["email"], "@")
+
+	customer, ok := got["customer"].(map[string]interface{})
+	require.True(t, ok)
+	require.NotEmpty(t, customer["firstName"])
+
+	billingAddress, ok := customer["billingAddress"].(map[string]interface{})
+	require.True(t, ok)
+	require.NotEmpty(t, billingAddress["city"])
+
+	contact, ok := billingAddress["contact"].(map[string]interface{})
+	require.True(t, ok)
+	require.Contains(t, contact["email"], "@")
+	require.NotEmpty(t, contact["comment"])
+}
+
+func TestBuilder_Build_IntegerExample(t *testing.T) {
+	a, err := parse.Parse("./testdata/integer.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	require.Equal(t, map[string]interface{}{
+		"small":    int64(42),
+		"large":    int64(1234567890123),
+		"negative": int64(-7),
+		"unsigned": uint64(18446744073709551615),
+	}, a.Operations[0].Responses[0].ExampleValue(""))
+}
+
+func TestBuilder_Build_ResponsesOrder(t *testing.T) {
+	a, err := parse.Parse("./testdata/status-codes.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	codes := make([]int, 0, len(a.Operations[0].Responses))
+	for _, r := range a.Operations[0].Responses {
+		codes = append(codes, r.StatusCode)
+	}
+
+	// default response is last with 200 status code
+	require.Equal(t, []int{200, 400, 500, 200}, codes)
+
+	got, err := a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: http.MethodGet,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, got.StatusCode)
+	require.Equal(t, map[string]interface{}{"id": "e1afccea-5168-4735-84d4-cb96f6fb5d25"}, got.ExampleValue(""))
+}
+
+func TestAPI_FindResponse_DefaultResponse(t *testing.T) {
+	a, err := parse.Parse("./testdata/default-response.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations[0].Responses, 1)
+	require.True(t, a.Operations[0].Responses[0].Default)
+
+	tests := []struct {
+		name             string
+		preferStatusCode int
+		want             int
+	}{
+		{
+			name:             "no preferred status code",
+			preferStatusCode: 0,
+			want:             http.StatusOK,
+		},
+		{
+			name:             "preferred status code",
+			preferStatusCode: http.StatusNotFound,
+			want:             http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:             "/users",
+				Method:           http.MethodGet,
+				PreferStatusCode: tc.preferStatusCode,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.StatusCode)
+			require.Equal(t, map[string]interface{}{"message": "ok"}, got.ExampleValue(""))
+		})
+	}
+}
+
+type countingFS struct {
+	files  fstest.MapFS
+	opened map[string]int
+}
+
+func (f countingFS) Open(name string) (fs.File, error) {
+	f.opened[name]++
+
+	return f.files.Open(name)
+}
+
+func TestBuilder_Build_ExternalReferencesCache(t *testing.T) {
+	fsys := countingFS{
+		files: fstest.MapFS{
+			"schemas.yml": {
+				Data: []byte(`User:
+  type: object
+  properties:
+    firstName:
+      type: string
+      example: Larry
+`),
+			},
+		},
+		opened: make(map[string]int),
+	}
+
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{
+							"200": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{
+											Type:  "array",
+											Items: &openapi.Schema{Ref: "schemas.yml#/User"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"/users/{userId}": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{
+							"200": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{Ref: "./schemas.yml#/User"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		FS:   fsys,
+		Base: "openapi.yml",
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 2)
+	require.Equal(t, map[string]int{"schemas.yml": 1}, fsys.opened)
+}
+
+func TestBuilder_Build_URLReferences(t *testing.T) {
+	docs := map[string]string{
+		"https://example.com/schemas/user.yml": `User:
+  type: object
+  properties:
+    firstName:
+      $ref: '#/Name'
+    address:
+      $ref: 'common.yml#/Address'
+Name:
+  type: string
+  example: Larry
+`,
+		"https://example.com/schemas/common.yml": `Address:
+  type: object
+  properties:
+    city:
+      type: string
+      example: Palo Alto
+`,
+	}
+
+	fetched := make(map[string]int)
+
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users/{userId}": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{
+							"200": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{Ref: "https://example.com/schemas/user.yml#/User"},
+									},
+								},
+							},
+						},
+					},
+				},
+				"/users": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{
+							"200": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{
+											Type:  "array",
+											Items: &openapi.Schema{Ref: "https://example.com/schemas/user.yml#/User"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Fetch: func(url string) ([]byte, error) {
+			fetched[url]++
+
+			doc, ok := docs[url]
+			if !ok {
+				return nil, fs.ErrNotExist
+			}
+
+			return []byte(doc), nil
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	operation, ok := a.FindOperation(http.MethodGet, "/users/1")
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{
+		"firstName": "Larry",
+		"address":   map[string]interface{}{"city": "Palo Alto"},
+	}, operation.Responses[0].ExampleValue(""))
+
+	require.Equal(t, map[string]int{
+		"https://example.com/schemas/user.yml":   1,
+		"https://example.com/schemas/common.yml": 1,
+	}, fetched)
+
+	t.Run("without fetch", func(t *testing.T) {
+		b := api.Builder{OpenAPI: b.OpenAPI}
+
+		_, err := b.Build()
+
+		var schemaError *openapi.SchemaError
+
+		require.ErrorAs(t, err, &schemaError)
+		require.Equal(t, "https://example.com/schemas/user.yml#/User", schemaError.Ref)
+	})
+}
+
+func TestBuilder_Build_RecursiveSchema(t *testing.T) {
+	a, err := parse.Parse("./testdata/recursive.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	require.Equal(t, map[string]interface{}{
+		"title":    "root",
+		"parent":   map[string]interface{}{},
+		"children": []interface{}{map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}},
+	}, a.Operations[0].Responses[0].ExampleValue(""))
+}
+
+func TestBuilder_Build_AllOf(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users": {
+					Post: &openapi.Operation{
+						RequestBody: openapi.RequestBody{
+							Content: openapi.Content{
+								"application/json": {
+									Schema: openapi.Schema{
+										AllOf: []*openapi.Schema{
+											{Ref: "#/components/schemas/Base"},
+											{Ref: "#/components/schemas/Person"},
+										},
+									},
+								},
+							},
+						},
+						Responses: openapi.Responses{
+							"201": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{
+											AllOf: []*openapi.Schema{
+												{Ref: "#/components/schemas/Base"},
+												{Ref: "#/components/schemas/Person"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Components: openapi.Components{
+				Schemas: openapi.Schemas{
+					"Base": {
+						Type:     "object",
+						Required: []string{"id", "name"},
+						Properties: openapi.Schemas{
+							"id":   {Type: "string", Example: "e1afccea-5168-4735-84d4-cb96f6fb5d25"},
+							"name": {Type: "string", Example: "base"},
+						},
+					},
+					"Person": {
+						Type:     "object",
+						Required: []string{"name"},
+						Properties: openapi.Schemas{
+							"name":     {Type: "string", Example: "Elon Musk"},
+							"nickname": {Type: "string", Example: "elon"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	require.Equal(t, map[string]api.FieldType{
+		"id":       {Required: true, Type: "string"},
+		"name":     {Required: true, Type: "string"},
+		"nickname": {Required: false, Type: "string"},
+	}, a.Operations[0].Body)
+
+	require.Equal(t, api.ObjectSchema{
+		Properties: map[string]api.Schema{
+			"id":       api.StringSchema{Example: "e1afccea-5168-4735-84d4-cb96f6fb5d25"},
+			"name":     api.StringSchema{Example: "Elon Musk"},
+			"nickname": api.StringSchema{Example: "elon"},
+		},
+		Example: map[string]interface{}{},
+	}, a.Operations[0].Responses[0].Schema)
+}
+
+func TestBuilder_Build_AllOfExamples(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{
+							"200": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{
+											AllOf: []*openapi.Schema{
+												{Ref: "#/components/schemas/Base"},
+												{
+													Type: "object",
+													Properties: openapi.Schemas{
+														"name": {Type: "string"},
+													},
+													Example: map[string]interface{}{"name": "Elon Musk"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Components: openapi.Components{
+				Schemas: openapi.Schemas{
+					"Base": {
+						Type: "object",
+						Properties: openapi.Schemas{
+							"id": {Type: "string"},
+						},
+						Example: map[string]interface{}{"id": "e1afccea-5168-4735-84d4-cb96f6fb5d25", "name": "base"},
+					},
+				},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"id":   "e1afccea-5168-4735-84d4-cb96f6fb5d25",
+		"name": "Elon Musk",
+	}, a.Operations[0].Responses[0].ExampleValue(""))
+}
+
+func TestBuilder_Build_OneOfAnyOf(t *testing.T) {
+	spec, err := os.ReadFile("./testdata/one-of.yml")
+	require.NoError(t, err)
+
+	oapi, err := openapi.Parse(spec)
+	require.NoError(t, err)
+
+	cat := map[string]interface{}{"kind": "cat", "lives": int64(9)}
+	dog := map[string]interface{}{"kind": "dog", "goodBoy": true}
+
+	t.Run("first branch", func(t *testing.T) {
+		b := api.Builder{OpenAPI: oapi}
+
+		a, err := b.Build()
+		require.NoError(t, err)
+
+		pet, ok := a.FindOperation(http.MethodGet, "/pets/1")
+		require.True(t, ok)
+		require.Equal(t, cat, pet.Responses[0].ExampleValue(""))
+
+		owner, ok := a.FindOperation(http.MethodGet, "/owners/1/pet")
+		require.True(t, ok)
+		require.Equal(t, map[string]interface{}{"name": "Elon", "kind": "dog", "goodBoy": true}, owner.Responses[0].ExampleValue(""))
+	})
+
+	t.Run("random branch is stable", func(t *testing.T) {
+		b := api.Builder{OpenAPI: oapi, Rand: api.NewRand(1)}
+
+		a, err := b.Build()
+		require.NoError(t, err)
+
+		pet, ok := a.FindOperation(http.MethodGet, "/pets/1")
+		require.True(t, ok)
+
+		got := pet.Responses[0].ExampleValue("")
+		require.Contains(t, []interface{}{cat, dog}, got)
+
+		for i := 0; i < 10; i++ {
+			require.Equal(t, got, pet.Responses[0].ExampleValue(""))
+		}
+	})
+}
+
+func TestBuilder_Build_DiscriminatorMapping(t *testing.T) {
+	spec, err := os.ReadFile("./testdata/discriminator-mapping.yml")
+	require.NoError(t, err)
+
+	oapi, err := openapi.Parse(spec)
+	require.NoError(t, err)
+
+	cat := map[string]interface{}{"petType": "cat", "name": "Tom", "lives": int64(9)}
+	dog := map[string]interface{}{"petType": "dog", "name": "Tom", "goodBoy": true}
+
+	t.Run("first mapped schema", func(t *testing.T) {
+		b := api.Builder{OpenAPI: oapi}
+
+		a, err := b.Build()
+		require.NoError(t, err)
+
+		pet, ok := a.FindOperation(http.MethodGet, "/pets/1")
+		require.True(t, ok)
+		require.Equal(t, cat, pet.Responses[0].ExampleValue(""))
+		require.Equal(t, dog, pet.Responses[0].WithVariant("dog").ExampleValue(""))
+	})
+
+	t.Run("random mapped schema", func(t *testing.T) {
+		b := api.Builder{OpenAPI: oapi, Rand: api.NewRand(1)}
+
+		a, err := b.Build()
+		require.NoError(t, err)
+
+		pet, ok := a.FindOperation(http.MethodGet, "/pets/1")
+		require.True(t, ok)
+
+		got, ok := pet.Responses[0].ExampleValue("").(map[string]interface{})
+		require.True(t, ok)
+		require.Contains(t, []interface{}{"cat", "dog"}, got["petType"])
+	})
+}
+
+func TestBuilder_Build_Enum(t *testing.T) {
+	a, err := parse.Parse("./testdata/enum.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	got, ok := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+	require.True(t, ok)
+
+	require.Contains(t, []interface{}{"active", "suspended", "closed"}, got["status"])
+	require.Contains(t, []interface{}{int64(1), int64(2), int64(3)}, got["tier"])
+	require.Equal(t, "pro", got["plan"])
+}
+
+func TestBuilder_Build_Default(t *testing.T) {
+	a, err := parse.Parse("./testdata/default.yml")
+	require.NoError(t, err)
+	require.Len(t, a.Operations, 1)
+
+	require.Equal(t, map[string]interface{}{
+		"status": "active",
+		"limit":  int64(20),
+		"email":  "admin@example.com",
+		"city":   "London",
+		"plan":   "pro",
+	}, a.Operations[0].Responses[0].ExampleValue(""))
+}
+
+func TestBuilder_Build_AdditionalProperties(t *testing.T) {
+	a, err := parse.Parse("./testdata/additional-properties.yml")
+	require.NoError(t, err)
+
+	counts, ok := a.FindOperation(http.MethodGet, "/counts")
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{
+		"additionalProp1": int64(7),
+		"additionalProp2": int64(7),
+	}, counts.Responses[0].ExampleValue(""))
+
+	labels, ok := a.FindOperation(http.MethodGet, "/labels")
+	require.True(t, ok)
+
+	example, ok := labels.Responses[0].ExampleValue("").(map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, example, 2)
+	require.IsType(t, "", example["additionalProp1"])
+	require.IsType(t, "", example["additionalProp2"])
+
+	users, ok := a.FindOperation(http.MethodPost, "/users")
+	require.True(t, ok)
+	require.True(t, users.BodyStrict)
+	require.Nil(t, users.BodyAdditionalProperties)
+
+	scores, ok := a.FindOperation(http.MethodPost, "/scores")
+	require.True(t, ok)
+	require.False(t, scores.BodyStrict)
+	require.Equal(t, &api.FieldType{Type: "number"}, scores.BodyAdditionalProperties)
+}
+
+func TestBuilder_Build_BasePaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		basePaths []string
+		want      []string
+	}{
+		{
+			name:      "servers",
+			basePaths: nil,
+			want: []string{
+				"/staging/v1", "/staging/v1/users/{userId}",
+				"/v1", "/v1/users/{userId}",
+				"/v2", "/v2/users/{userId}",
+			},
+		},
+		{
+			name:      "override",
+			basePaths: []string{"/v2"},
+			want:      []string{"/v2", "/v2/users/{userId}"},
+		},
+		{
+			name:      "no prefix",
+			basePaths: []string{"/"},
+			want:      []string{"/", "/users/{userId}"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := make([]parse.Option, 0, len(tc.basePaths))
+			for _, p := range tc.basePaths {
+				opts = append(opts, parse.WithBasePath(p))
+			}
+
+			a, err := parse.ParseWithOptions("./testdata/servers.yml", opts...)
+			require.NoError(t, err)
+
+			paths := make([]string, 0, len(a.Operations))
+			for _, op := range a.Operations {
+				paths = append(paths, op.Path)
+			}
+
+			sort.Strings(paths)
+
+			require.Equal(t, tc.want, paths)
+		})
+	}
+
+	a, err := parse.Parse("./testdata/servers.yml")
+	require.NoError(t, err)
+
+	res, err := a.FindResponse(api.FindResponseParams{
+		Path:   "/v1/users/42",
+		Method: http.MethodGet,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"id": "42"}, res.ExampleValue(""))
+
+	_, err = a.FindResponse(api.FindResponseParams{
+		Path:   "/users/42",
+		Method: http.MethodGet,
+	})
+	require.Error(t, err)
+}
+
+func TestExampleError(t *testing.T) {
+	got := &api.ExampleError{
+		Mismatches: []api.ExampleMismatch{
+			{Method: "GET", Path: "/users", StatusCode: 200, Message: "field id is required"},
+			{Method: "GET", Path: "/users", StatusCode: 200, Example: "test", Message: "example must be object"},
+		},
+	}
+
+	require.Equal(t, got.Error(), "examples do not match schemas: "+
+		"GET /users 200 example: field id is required; GET /users 200 example test: example must be object")
+
+	got = &api.ExampleError{
+		Mismatches: []api.ExampleMismatch{
+			{Method: "GET", Path: "/users", In: "query parameter limit", Example: "words", Message: "example must be integer"},
+		},
+	}
+
+	require.Equal(t, got.Error(), "examples do not match schemas: GET /users query parameter limit example words: example must be integer")
+}
+
+func TestBuilder_Build_Strict(t *testing.T) {
+	_, err := parse.Parse("./testdata/strict.yml")
+	require.NoError(t, err)
+
+	_, err = parse.ParseWithOptions("./testdata/strict.yml", parse.WithStrict())
+
+	var exampleError *api.ExampleError
+
+	require.ErrorAs(t, err, &exampleError)
+	require.Equal(t, []api.ExampleMismatch{
+		{Method: "GET", Path: "/users", In: "query parameter limit", Example: "words", Message: "example must be integer"},
+		{Method: "POST", Path: "/users", In: "requestBody", Message: "field name is required"},
+		{Method: "GET", Path: "/users/{userId}", StatusCode: 200, Example: "anonymous", Message: "field name is required"},
+		{Method: "GET", Path: "/users/{userId}", StatusCode: 200, Example: "misspelled", Message: "field name is required"},
+		{Method: "GET", Path: "/users/{userId}", StatusCode: 200, Example: "misspelled", Message: "field id must be integer"},
+	}, exampleError.Mismatches)
+
+	_, err = parse.ParseWithOptions("./testdata/template.yml", parse.WithStrict())
+	require.NoError(t, err)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Failure is mode of failure injected into responses
+type Failure string
+
+// Failure modes
+const (
+	// FailureStatus responds with 5xx status code instead of response
+	FailureStatus Failure = "status"
+	// FailureTruncate cuts response body in half, so it is malformed
+	FailureTruncate Failure = "truncate"
+	// FailureSlow delays response by Chaos.Delay
+	FailureSlow Failure = "slow"
+)
+
+//nolint:gochecknoglobals // failures are modes injected when Chaos.Failures is empty
+var failures = []Failure{FailureStatus, FailureTruncate, FailureSlow}
+
+//nolint:gochecknoglobals // failureStatusCodes are status codes of FailureStatus
+var failureStatusCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultChaosDelay is delay of FailureSlow when Chaos.Delay is zero
+const DefaultChaosDelay = 5 * time.Second
+
+// Chaos injects failures into responses for resilience testing of clients, zero value injects nothing
+type Chaos struct {
+	// Rate is probability of failure, from 0 to 1
+	Rate float64
+	// Failures are injected modes, any mode is injected when empty
+	Failures []Failure
+	// Delay is duration of FailureSlow, DefaultChaosDelay is used when zero
+	Delay time.Duration
+}
+
+// FailureError -.
+type FailureError struct {
+	Value string
+}
+
+// Error -.
+func (e *FailureError) Error() string {
+	return "unknown failure mode " + e.Value + ", expected status, truncate or slow"
+}
+
+// ParseFailures returns failure modes of comma-separated list, e.g. `status,slow`
+func ParseFailures(s string) ([]Failure, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(s, ",")
+	res := make([]Failure, 0, len(parts))
+
+	for _, p := range parts {
+		f := Failure(strings.TrimSpace(p))
+
+		switch f {
+		case FailureStatus, FailureTruncate, FailureSlow:
+			res = append(res, f)
+		default:
+			return nil, &FailureError{Value: p}
+		}
+	}
+
+	return res, nil
+}
+
+// Failure returns failure mode injected into response with probability of Rate,
+// empty mode is returned when no failure is injected or rnd is not set
+func (c Chaos) Failure(rnd *rand.Rand) Failure {
+	if c.Rate <= 0 || nil == rnd {
+		return ""
+	}
+
+	if rnd.Float64() >= c.Rate {
+		return ""
+	}
+
+	modes := c.Failures
+	if len(modes) == 0 {
+		modes = failures
+	}
+
+	return modes[rnd.Intn(len(modes))]
+}
+
+// StatusCode returns random 5xx status code of FailureStatus
+func (c Chaos) StatusCode(rnd *rand.Rand) int {
+	if nil == rnd {
+		return http.StatusInternalServerError
+	}
+
+	return failureStatusCodes[rnd.Intn(len(failureStatusCodes))]
+}
+
+// SlowDelay returns delay of FailureSlow
+func (c Chaos) SlowDelay() time.Duration {
+	if c.Delay > 0 {
+		return c.Delay
+	}
+
+	return DefaultChaosDelay
+}
@@ -0,0 +1,78 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+func TestFailureError(t *testing.T) {
+	got := &api.FailureError{
+		Value: "crash",
+	}
+
+	require.Equal(t, got.Error(), "unknown failure mode crash, expected status, truncate or slow")
+}
+
+func TestParseFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []api.Failure
+		err   error
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  nil,
+			err:   nil,
+		},
+		{
+			name:  "modes",
+			value: "status, slow",
+			want:  []api.Failure{api.FailureStatus, api.FailureSlow},
+			err:   nil,
+		},
+		{
+			name:  "unknown mode",
+			value: "status,crash",
+			want:  nil,
+			err:   &api.FailureError{Value: "crash"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := api.ParseFailures(tc.value)
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestChaos_Failure(t *testing.T) {
+	rnd := api.NewRand(1)
+
+	for i := 0; i < 100; i++ {
+		require.Empty(t, api.Chaos{}.Failure(rnd))
+		require.Empty(t, api.Chaos{Rate: 1}.Failure(nil))
+		require.Equal(t, api.FailureTruncate, api.Chaos{Rate: 1, Failures: []api.Failure{api.FailureTruncate}}.Failure(rnd))
+		require.Contains(t, []api.Failure{api.FailureStatus, api.FailureTruncate, api.FailureSlow}, api.Chaos{Rate: 1}.Failure(rnd))
+	}
+
+	// same seed injects same failures
+	first, second := api.NewRand(42), api.NewRand(42)
+	chaos := api.Chaos{Rate: 0.5}
+
+	for i := 0; i < 100; i++ {
+		require.Equal(t, chaos.Failure(first), chaos.Failure(second))
+	}
+}
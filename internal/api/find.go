@@ -1,11 +1,15 @@
 package api
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // FindResponseError -.
@@ -21,96 +25,860 @@ func (e *FindResponseError) Error() string {
 
 // FindResponseParams -.
 type FindResponseParams struct {
-	Path      string
-	Method    string
-	Body      io.ReadCloser
-	MediaType string
+	Path   string
+	Method string
+	Query  url.Values
+	Body   io.ReadCloser
+	// Client is address of client, e.g. remote address of request, rate limits are counted by client
+	Client string
+	// ContentType is media type of request body, e.g. from Content-Type header, body is decoded as JSON by default
+	ContentType string
+	// Header contains request headers, e.g. credentials of secured operations
+	Header http.Header
+	// Accept is value of Accept header used for content negotiation
+	Accept string
+	// PreferExample is name of example requested by client, e.g. by `Prefer: example=<name>` header
+	PreferExample string
+	// Variant is name of oneOf or anyOf branch of response schema requested by client, e.g. by `X-Dummy-Variant` header
+	Variant string
+	// PreferStatusCode is status code of response requested by client, e.g. by `Prefer: code=404` header
+	PreferStatusCode int
 }
 
 // ErrEmptyRequireField -.
 var ErrEmptyRequireField = errors.New("empty require field")
 
+// ErrMethodNotAllowed is returned when operations of path do not include method of request
+var ErrMethodNotAllowed = errors.New("method not allowed")
+
+// PropertiesCountError -.
+type PropertiesCountError struct {
+	Count int
+	Min   int
+	Max   int
+}
+
+// Error -.
+func (e *PropertiesCountError) Error() string {
+	if e.Max == 0 {
+		return fmt.Sprintf("request body has %d properties, expected at least %d", e.Count, e.Min)
+	}
+
+	return fmt.Sprintf("request body has %d properties, expected from %d to %d", e.Count, e.Min, e.Max)
+}
+
+// FieldTypeError -.
+type FieldTypeError struct {
+	Field string
+	Type  string
+}
+
+// Error -.
+func (e *FieldTypeError) Error() string {
+	return "field " + e.Field + " must be " + e.Type
+}
+
+// EnumError -.
+type EnumError struct {
+	Field string
+	Value interface{}
+}
+
+// Error -.
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("field %s has value %v not allowed by enum", e.Field, e.Value)
+}
+
+// UnknownFieldError -.
+type UnknownFieldError struct {
+	Field string
+}
+
+// Error -.
+func (e *UnknownFieldError) Error() string {
+	return "field " + e.Field + " is not allowed"
+}
+
+// RangeError -.
+type RangeError struct {
+	Field string
+	Value float64
+	// Bound is name of violated bound, e.g. `maximum`
+	Bound string
+	Limit float64
+}
+
+// Error -.
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("field %s value %v violates %s %v", e.Field, e.Value, e.Bound, e.Limit)
+}
+
+// LengthError -.
+type LengthError struct {
+	Field  string
+	Length int
+	Min    int
+	Max    *int
+}
+
+// Error -.
+func (e *LengthError) Error() string {
+	if nil == e.Max {
+		return fmt.Sprintf("field %s has length %d, expected at least %d", e.Field, e.Length, e.Min)
+	}
+
+	return fmt.Sprintf("field %s has length %d, expected from %d to %d", e.Field, e.Length, e.Min, *e.Max)
+}
+
+// PatternError -.
+type PatternError struct {
+	Field   string
+	Pattern string
+}
+
+// Error -.
+func (e *PatternError) Error() string {
+	return "field " + e.Field + " does not match pattern " + e.Pattern
+}
+
+// FormatError -.
+type FormatError struct {
+	Field  string
+	Format string
+}
+
+// Error -.
+func (e *FormatError) Error() string {
+	return "field " + e.Field + " must be " + e.Format
+}
+
+// StatusCodeError -.
+type StatusCodeError struct {
+	StatusCode int
+}
+
+// Error -.
+func (e *StatusCodeError) Error() string {
+	return fmt.Sprintf("operation has no response with status code %d", e.StatusCode)
+}
+
 // FindResponse -.
 func (a API) FindResponse(params FindResponseParams) (Response, error) {
+	_, response, err := a.Match(params)
+
+	return response, err
+}
+
+// Match returns operation matching request and its response, operation is returned with error of request
+// to matched operation, e.g. validation error
+func (a API) Match(params FindResponseParams) (Operation, Response, error) {
+	params = params.normalize()
+
 	operation, ok := a.findOperation(params)
+	if !ok && len(a.Methods(params.Path)) > 0 {
+		return Operation{}, Response{}, fmt.Errorf("%w: %s %s", ErrMethodNotAllowed, params.Method, params.Path)
+	}
+
 	if !ok {
-		return Response{}, &FindResponseError{
+		return Operation{}, Response{}, &FindResponseError{
 			Method: params.Method,
 			Path:   params.Path,
 		}
 	}
 
-	switch params.Method {
-	case http.MethodPost, http.MethodPut, http.MethodPatch:
-		var body map[string]interface{}
+	if !operation.authorized(params) {
+		return operation, Response{}, ErrUnauthorized
+	}
+
+	if a.RateLimiter != nil {
+		if err := a.RateLimiter.Allow(operation, params.Path, params.Client, now(a.Clock)); err != nil {
+			return operation, Response{}, err
+		}
+	}
+
+	if err := a.checkParams(operation, params); err != nil {
+		return operation, Response{}, err
+	}
+
+	var body map[string]interface{}
+
+	if a.validateBody(params.Method, operation) {
+		var err error
+
+		rules := operation.bodyRules(params.ContentType)
+
+		switch {
+		case isForm(params.ContentType):
+			body, err = decodeForm(params.Body, rules.Body)
+		case isMultipart(params.ContentType):
+			body, err = decodeMultipart(params.Body, params.ContentType, rules)
+		default:
+			body, err = a.decodeBody(params.Body)
+		}
+
+		switch {
+		case err == nil:
+			if err := a.checkFields(rules, body); err != nil {
+				return operation, Response{}, err
+			}
+		case a.Validation != ValidationOff:
+			// fields of malformed body are not checked
+			if err := a.enforce(operation, decodeError(err), "malformed request body"); err != nil {
+				return operation, Response{}, err
+			}
+		}
+	}
+
+	statusCode := operation.defaultStatusCode()
+	if params.PreferStatusCode != 0 {
+		statusCode = params.PreferStatusCode
+	}
 
-		err := json.NewDecoder(params.Body).Decode(&body)
+	response, ok := operation.negotiate(statusCode, params.Accept)
+	if !ok {
+		return operation, Response{}, &StatusCodeError{StatusCode: statusCode}
+	}
+
+	if params.Variant != "" {
+		response = response.WithVariant(params.Variant)
+	}
+
+	if a.Echo {
+		response.RequestBody = body
+	}
+
+	pathParams, _ := PathParams(params.Path, operation.Path)
+
+	response.Request = &RequestValues{
+		Params:      pathParams,
+		TypedParams: operation.typedPathParams(pathParams),
+		Query:       operation.queryValues(params.Query),
+		Header:      operation.headerValues(params.Header),
+		Cookie:      operation.cookieValues(params.Header),
+		Body:        body,
+	}
+
+	if a.Store != nil {
+		value, ok, err := a.Store.stateful(params.Method, params.Path, operation, body)
 		if err != nil {
-			return Response{}, err
+			return operation, Response{}, err
 		}
 
-		for k, v := range operation.Body {
-			_, ok := body[k]
-			if !ok && v.Required {
-				return Response{}, ErrEmptyRequireField
+		if ok {
+			response.Value = value
+		}
+	}
+
+	if nil == response.Value && a.paginated(params.Method) {
+		if envelope, ok := response.paginate(ParsePage(params.Query)); ok {
+			response.Value = envelope
+		}
+	}
+
+	// absent example falls back to default one
+	if _, ok := response.Examples[params.PreferExample]; ok {
+		response.ExampleKey = params.PreferExample
+	}
+
+	return operation, response, nil
+}
+
+// FindExamples returns all named examples of operation responses keyed by example name
+func (a API) FindExamples(method, path string) (map[string]interface{}, error) {
+	operation, ok := a.FindOperation(method, path)
+	if !ok {
+		return nil, &FindResponseError{
+			Method: method,
+			Path:   path,
+		}
+	}
+
+	examples := make(map[string]interface{})
+
+	for _, r := range operation.Responses {
+		for name, example := range r.Examples {
+			// empty key duplicates the default example
+			if name == "" {
+				continue
 			}
+
+			examples[name] = example
 		}
 	}
 
-	response, ok := operation.findResponse(params)
+	return examples, nil
+}
+
+// match reports whether JSON decoded value matches declared field type, unknown types match any value
+func (f FieldType) match(value interface{}) bool {
+	switch f.Type {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		v, ok := value.(float64)
+		return ok && v == math.Trunc(v)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+// allowed reports whether value is member of field enum. Values are compared by string representation,
+// since JSON decoded numbers are float64 while enum numbers are integers.
+func (f FieldType) allowed(value interface{}) bool {
+	if len(f.Enum) == 0 {
+		return true
+	}
+
+	for _, e := range f.Enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateString returns error if string value violates length or pattern constraints of field,
+// values of other types are not checked. Pattern is not anchored, e.g. `[0-9]` matches `a1b`.
+func (f FieldType) validateString(field string, value interface{}) *ValidationError {
+	s, ok := value.(string)
 	if !ok {
-		return operation.Responses[0], nil
+		return nil
 	}
 
-	return response, nil
+	if n := utf8.RuneCountInString(s); n < f.MinLength || (f.MaxLength != nil && n > *f.MaxLength) {
+		return &ValidationError{
+			Field: field,
+			Code:  CodeInvalidLength,
+			Err: &LengthError{
+				Field:  field,
+				Length: n,
+				Min:    f.MinLength,
+				Max:    f.MaxLength,
+			},
+		}
+	}
+
+	if f.Pattern != nil && !f.Pattern.MatchString(s) {
+		return &ValidationError{
+			Field: field,
+			Code:  CodePatternMismatch,
+			Err: &PatternError{
+				Field:   field,
+				Pattern: f.Pattern.String(),
+			},
+		}
+	}
+
+	if !validFormat(f.Format, s) {
+		return &ValidationError{
+			Field: field,
+			Code:  CodeInvalidFormat,
+			Err: &FormatError{
+				Field:  field,
+				Format: f.Format,
+			},
+		}
+	}
+
+	return nil
+}
+
+func (o Operation) validatePropertiesCount(count int) error {
+	if count >= o.BodyMinProperties && (o.BodyMaxProperties == 0 || count <= o.BodyMaxProperties) {
+		return nil
+	}
+
+	return &PropertiesCountError{
+		Count: count,
+		Min:   o.BodyMinProperties,
+		Max:   o.BodyMaxProperties,
+	}
+}
+
+// bodyRules returns operation with request body validation rules of media type of content type
+func (o Operation) bodyRules(contentType string) Operation {
+	if rules, ok := o.BodyMediaTypes[mediaTypeOf(contentType)]; ok {
+		return rules
+	}
+
+	return o
+}
+
+// checkFields returns validation error of request body according to validation mode of API
+func (a API) checkFields(o Operation, body map[string]interface{}) error {
+	if a.Validation == ValidationOff {
+		return nil
+	}
+
+	return a.enforce(o, o.validateFields(body), "invalid request body")
+}
+
+// checkParams returns validation error of query, header and cookie parameters according to validation mode
+func (a API) checkParams(o Operation, params FindResponseParams) error {
+	if a.Validation == ValidationOff {
+		return nil
+	}
+
+	return a.enforce(o, o.validateParams(params), "invalid request parameters")
 }
 
+// enforce returns validation error in strict mode, in warn mode error is logged with message
+func (a API) enforce(o Operation, err error, msg string) error {
+	if err == nil || a.Validation == ValidationStrict {
+		return err
+	}
+
+	if a.Logger != nil {
+		a.Logger.Warn().Err(err).Str("method", o.Method).Str("path", o.Path).Msg(msg)
+	}
+
+	return nil
+}
+
+// validateBody reports whether request body should be decoded and validated
+func (a API) validateBody(method string, o Operation) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	default:
+		return a.ValidateAnyMethodBody && o.Body != nil
+	}
+}
+
+// FindOperation returns operation by method and path
+func (a API) FindOperation(method, path string) (Operation, bool) {
+	params := FindResponseParams{
+		Path:   path,
+		Method: method,
+	}
+
+	return a.findOperation(params.normalize())
+}
+
+// normalize returns params with normalized path and uppercase method, since methods of operations are
+// uppercase, while some clients send custom or lowercase methods as is
+func (p FindResponseParams) normalize() FindResponseParams {
+	p.Path = NormalizePath(p.Path)
+	p.Method = strings.ToUpper(p.Method)
+
+	return p
+}
+
+// findOperation returns operation matching normalized method and path, HEAD request without HEAD operation
+// matches GET operation
 func (a API) findOperation(params FindResponseParams) (Operation, bool) {
-	for _, op := range a.Operations {
-		if !PathByParamDetect(params.Path, op.Path) {
-			continue
+	operation, ok := a.matchOperation(params)
+	if !ok && params.Method == http.MethodHead {
+		params.Method = http.MethodGet
+
+		return a.matchOperation(params)
+	}
+
+	return operation, ok
+}
+
+// matchOperation returns operation matching method and path. Operation with fewer parameter segments
+// is preferred, e.g. `/users/me` over `/users/{id}`. Among equally specific operations one with all
+// required query parameters present is preferred, operation without query requirements is fallback.
+func (a API) matchOperation(params FindResponseParams) (Operation, bool) {
+	var m match
+
+	if nil == a.router {
+		for _, op := range a.Operations {
+			if params.Method == op.Method && PathByParamDetect(params.Path, op.Path) && op.validPathParams(params.Path) {
+				m.consider(op, params.Query)
+			}
+		}
+
+		return m.operation, m.ok
+	}
+
+	a.router.walk(strings.Split(params.Path, "/"), func(operations []Operation) {
+		for _, op := range operations {
+			if params.Method == op.Method && op.validPathParams(params.Path) {
+				m.consider(op, params.Query)
+			}
 		}
+	})
 
-		if params.Method != op.Method {
+	return m.operation, m.ok
+}
+
+// validPathParams reports whether values of path parameters satisfy constraints of operation,
+// e.g. `/orders/latest` does not match `/orders/{id}` of integer id
+func (o Operation) validPathParams(path string) bool {
+	if len(o.PathParams) == 0 {
+		return true
+	}
+
+	values, ok := PathParams(path, o.Path)
+	if !ok {
+		return false
+	}
+
+	for name, field := range o.PathParams {
+		value, ok := values[name]
+		if !ok {
 			continue
 		}
 
-		return op, true
+		if field.validate(name, paramValue(value, field)) != nil {
+			return false
+		}
 	}
 
-	return Operation{}, false
+	return true
 }
 
-func (o Operation) findResponse(params FindResponseParams) (Response, bool) {
+// typedPathParams returns values of path parameters with types converted to them
+func (o Operation) typedPathParams(values map[string]string) map[string]interface{} {
+	res := make(map[string]interface{}, len(o.PathParams))
+
+	for name, field := range o.PathParams {
+		if value, ok := values[name]; ok && field.Type != "" && field.Type != "string" {
+			res[name] = formValue(value, field.Type)
+		}
+	}
+
+	return res
+}
+
+// queryValues returns first values of query parameters converted to types of declared ones
+func (o Operation) queryValues(query url.Values) map[string]interface{} {
+	res := make(map[string]interface{}, len(query))
+
+	for name, values := range query {
+		if len(values) > 0 {
+			res[name] = formValue(values[0], o.QueryParams[name].Type)
+		}
+	}
+
+	return res
+}
+
+// headerValues returns first values of request headers by canonical names,
+// values of declared header parameters are converted to their types
+func (o Operation) headerValues(header http.Header) map[string]interface{} {
+	res := make(map[string]interface{}, len(header))
+
+	for name, values := range header {
+		if len(values) > 0 {
+			res[http.CanonicalHeaderKey(name)] = values[0]
+		}
+	}
+
+	for name, field := range o.HeaderParams {
+		if value := header.Get(name); value != "" {
+			res[http.CanonicalHeaderKey(name)] = formValue(value, field.Type)
+		}
+	}
+
+	return res
+}
+
+// cookieValues returns values of request cookies, values of declared cookie parameters are converted to their types
+func (o Operation) cookieValues(header http.Header) map[string]interface{} {
+	cookies := (&http.Request{Header: header}).Cookies()
+	res := make(map[string]interface{}, len(cookies))
+
+	for _, c := range cookies {
+		if _, ok := res[c.Name]; !ok {
+			res[c.Name] = formValue(c.Value, o.CookieParams[c.Name].Type)
+		}
+	}
+
+	return res
+}
+
+// Methods returns sorted methods allowed for path: methods of operations matching path,
+// HEAD along with GET and OPTIONS. No methods are returned for unknown path.
+func (a API) Methods(path string) []string {
+	path = NormalizePath(path)
+
+	methods := make(map[string]bool)
+
+	if nil == a.router {
+		for _, op := range a.Operations {
+			if PathByParamDetect(path, op.Path) && op.validPathParams(path) {
+				methods[op.Method] = true
+			}
+		}
+	} else {
+		a.router.walk(strings.Split(path, "/"), func(operations []Operation) {
+			for _, op := range operations {
+				if op.validPathParams(path) {
+					methods[op.Method] = true
+				}
+			}
+		})
+	}
+
+	if len(methods) == 0 {
+		return nil
+	}
+
+	if methods[http.MethodGet] {
+		methods[http.MethodHead] = true
+	}
+
+	methods[http.MethodOptions] = true
+
+	res := make([]string, 0, len(methods))
+	for method := range methods {
+		res = append(res, method)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// match is most specific operation among considered ones
+type match struct {
+	operation Operation
+	catchAll  bool
+	params    int
+	score     int
+	ok        bool
+}
+
+func (m *match) consider(op Operation, query url.Values) {
+	catchAll, params, score := op.catchAll(), op.paramCount(), op.queryScore(query)
+
+	if m.ok && !m.worse(catchAll, params, score, op.Path) {
+		return
+	}
+
+	*m = match{
+		operation: op,
+		catchAll:  catchAll,
+		params:    params,
+		score:     score,
+		ok:        true,
+	}
+}
+
+// worse reports whether matched operation is less specific than operation of given properties,
+// operation without catch-all parameter is more specific than one with it. Equally specific operations
+// are ordered by path template, so match does not depend on order of operations.
+func (m *match) worse(catchAll bool, params, score int, path string) bool {
+	switch {
+	case catchAll != m.catchAll:
+		return !catchAll
+	case params != m.params:
+		return params < m.params
+	case score != m.score:
+		return score > m.score
+	default:
+		return path < m.operation.Path
+	}
+}
+
+// catchAll reports whether operation path ends with catch-all parameter
+func (o Operation) catchAll() bool {
+	return isCatchAllParam(o.Path[strings.LastIndex(o.Path, "/")+1:])
+}
+
+// paramCount returns count of parameter segments of operation path
+func (o Operation) paramCount() int {
+	count := 0
+
+	for _, segment := range strings.Split(o.Path, "/") {
+		if isParam(segment) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// queryScore returns 0 when required query parameters are not present,
+// otherwise count of required query parameters increased by one
+func (o Operation) queryScore(query url.Values) int {
+	for name, value := range o.Query {
+		if _, ok := query[name]; !ok {
+			return 0
+		}
+
+		if value != "" && query.Get(name) != value {
+			return 0
+		}
+	}
+
+	return len(o.Query) + 1
+}
+
+// negotiate returns response with status code of media type best matching Accept header value,
+// first response with status code is returned when nothing matches. Response of range of status codes,
+// e.g. `4XX`, and then default response is used with requested status code when operation has no response
+// with status code.
+func (o Operation) negotiate(statusCode int, accept string) (Response, bool) {
+	ranges := parseAccept(accept)
+
+	found, ok := o.bestResponse(ranges, func(r Response) bool {
+		return !r.Default && r.Range == 0 && r.StatusCode == statusCode
+	})
+	if ok {
+		return found, true
+	}
+
+	found, ok = o.bestResponse(ranges, func(r Response) bool {
+		return r.Range > 0 && r.Range == statusCode/100
+	})
+	if !ok {
+		found, ok = o.bestResponse(ranges, func(r Response) bool {
+			return r.Default
+		})
+	}
+
+	if !ok {
+		return Response{}, false
+	}
+
+	found.StatusCode = statusCode
+
+	return found, true
+}
+
+// bestResponse returns response of media type best matching accepted ranges among responses satisfying match
+func (o Operation) bestResponse(ranges mediaRanges, match func(r Response) bool) (Response, bool) {
+	var (
+		found   Response
+		quality float64
+		ok      bool
+	)
+
 	for _, r := range o.Responses {
-		if r.MediaType != params.MediaType {
+		if !match(r) {
 			continue
 		}
 
-		return r, true
+		if !ok {
+			found, ok = r, true
+		}
+
+		if q := ranges.quality(r.MediaType); q > quality {
+			found, quality = r, q
+		}
+	}
+
+	return found, ok
+}
+
+// defaultStatusCode returns lowest 2xx status code of responses, or status code of first response if there
+// is no such one. Responses are sorted by status code. Operation without responses has no response of 200.
+func (o Operation) defaultStatusCode() int {
+	for _, r := range o.Responses {
+		if r.StatusCode >= http.StatusOK && r.StatusCode < http.StatusMultipleChoices {
+			return r.StatusCode
+		}
+	}
+
+	if len(o.Responses) == 0 {
+		return http.StatusOK
 	}
 
-	return Response{}, false
+	return o.Responses[0].StatusCode
 }
 
 // PathByParamDetect returns result of
+// matching path against path template. Trailing optional parameter `{name?}` may be omitted in path.
 func PathByParamDetect(path, param string) bool {
+	_, ok := PathParams(path, param)
+
+	return ok
+}
+
+// PathParams returns values of parameter segments of path matching pattern and result of matching,
+// e.g. `{"userId": "42"}` for `/users/42` and `/users/{userId}`. Omitted optional parameter has no value.
+// Trailing catch-all parameter, e.g. `{path...}` or `{proxy+}`, matches one or more remaining segments,
+// e.g. `{"path": "a/b/c"}` for `/files/a/b/c` and `/files/{path...}`.
+func PathParams(path, pattern string) (map[string]string, bool) {
 	splitPath := strings.Split(path, "/")
-	splitParam := strings.Split(param, "/")
+	splitPattern := strings.Split(pattern, "/")
 
-	if len(splitPath) != len(splitParam) {
-		return false
+	last := splitPattern[len(splitPattern)-1]
+
+	if isCatchAllParam(last) {
+		n := len(splitPattern) - 1
+		if len(splitPath) <= n {
+			return nil, false
+		}
+
+		rest := strings.Join(splitPath[n:], "/")
+		if rest == "" {
+			return nil, false
+		}
+
+		params, ok := PathParams(strings.Join(splitPath[:n], "/"), strings.Join(splitPattern[:n], "/"))
+		if !ok {
+			return nil, false
+		}
+
+		params[paramName(last)] = rest
+
+		return params, true
 	}
 
+	if len(splitPath) == len(splitPattern)-1 && isOptionalParam(last) {
+		splitPattern = splitPattern[:len(splitPattern)-1]
+	}
+
+	if len(splitPath) != len(splitPattern) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
 	for i := 0; i < len(splitPath); i++ {
-		if strings.HasPrefix(splitParam[i], "{") && strings.HasSuffix(splitParam[i], "}") {
+		if isParam(splitPattern[i]) {
+			params[paramName(splitPattern[i])] = splitPath[i]
+
 			continue
 		}
 
-		if splitPath[i] != splitParam[i] {
-			return false
+		if splitPath[i] != splitPattern[i] {
+			return nil, false
 		}
 	}
 
-	return true
+	return params, true
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// isCatchAllParam reports whether segment is parameter matching remaining segments, e.g. `{path...}` or `{proxy+}`
+func isCatchAllParam(segment string) bool {
+	return isParam(segment) && (strings.HasSuffix(segment, "...}") || strings.HasSuffix(segment, "+}"))
+}
+
+// paramName returns name of parameter segment without braces and optional or catch-all suffix
+func paramName(segment string) string {
+	name := strings.Trim(segment, "{}")
+
+	for _, suffix := range []string{"?", "...", "+"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+
+	return name
+}
+
+func isOptionalParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "?}")
 }
@@ -1,11 +1,21 @@
 package api_test
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
 	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/logger"
+	"github.com/neotoolkit/dummy/internal/openapi"
+	"github.com/neotoolkit/dummy/internal/parse"
 )
 
 func TestPathByParamDetect(t *testing.T) {
@@ -57,6 +67,30 @@ func TestPathByParamDetect(t *testing.T) {
 			param: "/path/{1}/path/{2}",
 			want:  false,
 		},
+		{
+			name:  "optional segment present",
+			path:  "/items/5",
+			param: "/items/{id?}",
+			want:  true,
+		},
+		{
+			name:  "optional segment omitted",
+			path:  "/items",
+			param: "/items/{id?}",
+			want:  true,
+		},
+		{
+			name:  "optional segment and extra segment",
+			path:  "/items/5/6",
+			param: "/items/{id?}",
+			want:  false,
+		},
+		{
+			name:  "required segment omitted",
+			path:  "/items",
+			param: "/items/{id}",
+			want:  false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -68,6 +102,96 @@ func TestPathByParamDetect(t *testing.T) {
 	}
 }
 
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		pattern string
+		want    map[string]string
+		ok      bool
+	}{
+		{
+			name:    "static path",
+			path:    "/users",
+			pattern: "/users",
+			want:    map[string]string{},
+			ok:      true,
+		},
+		{
+			name:    "single parameter",
+			path:    "/users/42",
+			pattern: "/users/{userId}",
+			want:    map[string]string{"userId": "42"},
+			ok:      true,
+		},
+		{
+			name:    "multiple parameters",
+			path:    "/orgs/neotoolkit/users/42",
+			pattern: "/orgs/{orgId}/users/{userId}",
+			want:    map[string]string{"orgId": "neotoolkit", "userId": "42"},
+			ok:      true,
+		},
+		{
+			name:    "optional parameter present",
+			path:    "/items/5",
+			pattern: "/items/{id?}",
+			want:    map[string]string{"id": "5"},
+			ok:      true,
+		},
+		{
+			name:    "optional parameter omitted",
+			path:    "/items",
+			pattern: "/items/{id?}",
+			want:    map[string]string{},
+			ok:      true,
+		},
+		{
+			name:    "catch-all parameter",
+			path:    "/files/a/b/c",
+			pattern: "/files/{path...}",
+			want:    map[string]string{"path": "a/b/c"},
+			ok:      true,
+		},
+		{
+			name:    "catch-all parameter of single segment",
+			path:    "/orgs/neotoolkit/proxy/single",
+			pattern: "/orgs/{orgId}/proxy/{proxy+}",
+			want:    map[string]string{"orgId": "neotoolkit", "proxy": "single"},
+			ok:      true,
+		},
+		{
+			name:    "catch-all parameter without segments",
+			path:    "/files",
+			pattern: "/files/{path...}",
+			want:    nil,
+			ok:      false,
+		},
+		{
+			name:    "static segment mismatch",
+			path:    "/orgs/neotoolkit/members/42",
+			pattern: "/orgs/{orgId}/users/{userId}",
+			want:    nil,
+			ok:      false,
+		},
+		{
+			name:    "segment count mismatch",
+			path:    "/users/42/posts",
+			pattern: "/users/{userId}",
+			want:    nil,
+			ok:      false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := api.PathParams(tc.path, tc.pattern)
+
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestFindResponseError(t *testing.T) {
 	got := &api.FindResponseError{
 		Method: "test method",
@@ -76,3 +200,1529 @@ func TestFindResponseError(t *testing.T) {
 
 	require.Equal(t, got.Error(), "not specified operation: test method test path")
 }
+
+func TestStatusCodeError(t *testing.T) {
+	got := &api.StatusCodeError{
+		StatusCode: 404,
+	}
+
+	require.Equal(t, got.Error(), "operation has no response with status code 404")
+}
+
+func TestFieldTypeError(t *testing.T) {
+	got := &api.FieldTypeError{
+		Field: "id",
+		Type:  "string",
+	}
+
+	require.Equal(t, got.Error(), "field id must be string")
+}
+
+func TestAPI_FindResponse_FieldType(t *testing.T) {
+	a, err := parse.Parse("./testdata/typed-body.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "valid body",
+			body: `{"id": "1", "age": 42, "rating": 4.2, "active": true, "tags": ["a"], "address": {"city": "Paris"}}`,
+			err:  nil,
+		},
+		{
+			name: "missing required field",
+			body: `{"age": 42}`,
+			err:  api.ErrEmptyRequireField,
+		},
+		{
+			name: "string mismatch",
+			body: `{"id": 123}`,
+			err:  &api.FieldTypeError{Field: "id", Type: "string"},
+		},
+		{
+			name: "integer mismatch",
+			body: `{"id": "1", "age": 4.2}`,
+			err:  &api.FieldTypeError{Field: "age", Type: "integer"},
+		},
+		{
+			name: "number mismatch",
+			body: `{"id": "1", "rating": "high"}`,
+			err:  &api.FieldTypeError{Field: "rating", Type: "number"},
+		},
+		{
+			name: "boolean mismatch",
+			body: `{"id": "1", "active": "yes"}`,
+			err:  &api.FieldTypeError{Field: "active", Type: "boolean"},
+		},
+		{
+			name: "array mismatch",
+			body: `{"id": "1", "tags": "notarray"}`,
+			err:  &api.FieldTypeError{Field: "tags", Type: "array"},
+		},
+		{
+			name: "object mismatch",
+			body: `{"id": "1", "address": 5}`,
+			err:  &api.FieldTypeError{Field: "address", Type: "object"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/users",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEnumError(t *testing.T) {
+	got := &api.EnumError{
+		Field: "status",
+		Value: "deleted",
+	}
+
+	require.Equal(t, got.Error(), "field status has value deleted not allowed by enum")
+}
+
+func TestAPI_FindResponse_Enum(t *testing.T) {
+	a, err := parse.Parse("./testdata/enum.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "allowed values",
+			body: `{"status": "suspended", "tier": 2}`,
+			err:  nil,
+		},
+		{
+			name: "not allowed string",
+			body: `{"status": "deleted"}`,
+			err:  &api.EnumError{Field: "status", Value: "deleted"},
+		},
+		{
+			name: "not allowed integer",
+			body: `{"tier": 4}`,
+			err:  &api.EnumError{Field: "tier", Value: float64(4)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/accounts",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRangeError(t *testing.T) {
+	got := &api.RangeError{
+		Field: "test",
+		Value: 25,
+		Bound: "maximum",
+		Limit: 20,
+	}
+
+	require.Equal(t, got.Error(), "field test value 25 violates maximum 20")
+}
+
+func TestAPI_FindResponse_Range(t *testing.T) {
+	a, err := parse.Parse("./testdata/range.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "within range",
+			body: `{"score": 15, "ratio": 0.5}`,
+			err:  nil,
+		},
+		{
+			name: "inclusive bounds",
+			body: `{"score": 20, "level": 2}`,
+			err:  nil,
+		},
+		{
+			name: "greater than maximum",
+			body: `{"score": 25}`,
+			err:  &api.RangeError{Field: "score", Value: 25, Bound: "maximum", Limit: 20},
+		},
+		{
+			name: "less than minimum",
+			body: `{"score": 9}`,
+			err:  &api.RangeError{Field: "score", Value: 9, Bound: "minimum", Limit: 10},
+		},
+		{
+			name: "equal to exclusive minimum",
+			body: `{"ratio": 0}`,
+			err:  &api.RangeError{Field: "ratio", Value: 0, Bound: "exclusiveMinimum", Limit: 0},
+		},
+		{
+			name: "equal to exclusive maximum",
+			body: `{"ratio": 1}`,
+			err:  &api.RangeError{Field: "ratio", Value: 1, Bound: "exclusiveMaximum", Limit: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/scores",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLengthError(t *testing.T) {
+	max := 5
+
+	tests := []struct {
+		name string
+		err  *api.LengthError
+		want string
+	}{
+		{
+			name: "minimum",
+			err:  &api.LengthError{Field: "name", Length: 1, Min: 2},
+			want: "field name has length 1, expected at least 2",
+		},
+		{
+			name: "range",
+			err:  &api.LengthError{Field: "name", Length: 6, Min: 2, Max: &max},
+			want: "field name has length 6, expected from 2 to 5",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.err.Error())
+		})
+	}
+}
+
+func TestPatternError(t *testing.T) {
+	got := &api.PatternError{
+		Field:   "code",
+		Pattern: "^[A-Z]+$",
+	}
+
+	require.Equal(t, got.Error(), "field code does not match pattern ^[A-Z]+$")
+}
+
+func TestFormatError(t *testing.T) {
+	got := &api.FormatError{
+		Field:  "email",
+		Format: "email",
+	}
+
+	require.Equal(t, got.Error(), "field email must be email")
+}
+
+func TestAPI_FindResponse_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		valid  string
+		wrong  string
+	}{
+		{format: "date", valid: "2021-12-31", wrong: "31.12.2021"},
+		{format: "date-time", valid: "2021-12-31T23:59:59Z", wrong: "2021-12-31"},
+		{format: "email", valid: "elon@example.com", wrong: "Elon <elon@example.com>"},
+		{format: "uuid", valid: "e1afccea-5168-4735-84d4-cb96f6fb5d25", wrong: "e1afccea"},
+		{format: "uri", valid: "https://example.com", wrong: "example.com"},
+		{format: "ipv4", valid: "127.0.0.1", wrong: "::1"},
+		{format: "ipv6", valid: "::1", wrong: "127.0.0.1"},
+		{format: "unknown", valid: "anything", wrong: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			a := api.API{
+				Operations: []api.Operation{
+					{
+						Method: http.MethodPost,
+						Path:   "/values",
+						Body: map[string]api.FieldType{
+							"value": {Type: "string", Format: tc.format},
+						},
+						Responses: []api.Response{
+							{StatusCode: http.StatusCreated},
+						},
+					},
+				},
+			}
+
+			find := func(value string) error {
+				_, err := a.FindResponse(api.FindResponseParams{
+					Path:   "/values",
+					Method: http.MethodPost,
+					Body:   io.NopCloser(strings.NewReader(`{"value": "` + value + `"}`)),
+				})
+
+				return err
+			}
+
+			require.NoError(t, find(tc.valid))
+
+			if tc.wrong == "" {
+				return
+			}
+
+			var validationError *api.ValidationError
+
+			require.ErrorAs(t, find(tc.wrong), &validationError)
+			require.Equal(t, api.CodeInvalidFormat, validationError.Code)
+		})
+	}
+}
+
+func TestAPI_FindResponse_String(t *testing.T) {
+	a, err := parse.Parse("./testdata/string.yml")
+	require.NoError(t, err)
+
+	zero, five := 0, 5
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "valid values",
+			body: `{"code": "ABC-12", "digit": "a1b", "name": "Ada", "empty": ""}`,
+			err:  nil,
+		},
+		{
+			name: "anchored pattern",
+			body: `{"code": "ABC-123"}`,
+			err:  &api.PatternError{Field: "code", Pattern: "^[A-Z]{3}-[0-9]{2}$"},
+		},
+		{
+			name: "unanchored pattern",
+			body: `{"digit": "abc"}`,
+			err:  &api.PatternError{Field: "digit", Pattern: "[0-9]"},
+		},
+		{
+			name: "unsupported pattern",
+			body: `{"password": "secret"}`,
+			err:  nil,
+		},
+		{
+			name: "too short",
+			body: `{"name": "A"}`,
+			err:  &api.LengthError{Field: "name", Length: 1, Min: 2, Max: &five},
+		},
+		{
+			name: "too long",
+			body: `{"name": "Grace Hopper"}`,
+			err:  &api.LengthError{Field: "name", Length: 12, Min: 2, Max: &five},
+		},
+		{
+			name: "zero maximum length",
+			body: `{"empty": "a"}`,
+			err:  &api.LengthError{Field: "empty", Length: 1, Max: &zero},
+		},
+		{
+			name: "length in characters",
+			body: `{"name": "Åsa"}`,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/codes",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPI_FindResponse_Nullable(t *testing.T) {
+	a, err := parse.Parse("./testdata/nullable.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "null value of nullable field",
+			body: `{"nickname": null}`,
+			err:  nil,
+		},
+		{
+			name: "null value of not nullable field",
+			body: `{"bio": null}`,
+			err:  &api.FieldTypeError{Field: "bio", Type: "string"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/profiles",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUnknownFieldError(t *testing.T) {
+	got := &api.UnknownFieldError{
+		Field: "test",
+	}
+
+	require.Equal(t, got.Error(), "field test is not allowed")
+}
+
+func TestAPI_FindResponse_AdditionalProperties(t *testing.T) {
+	a, err := parse.Parse("./testdata/additional-properties.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		err  error
+	}{
+		{
+			name: "listed field of strict object",
+			path: "/users",
+			body: `{"name": "Elon"}`,
+			err:  nil,
+		},
+		{
+			name: "unknown field of strict object",
+			path: "/users",
+			body: `{"name": "Elon", "role": "admin"}`,
+			err:  &api.UnknownFieldError{Field: "role"},
+		},
+		{
+			name: "additional field of valid type",
+			path: "/scores",
+			body: `{"name": "Elon", "math": 5}`,
+			err:  nil,
+		},
+		{
+			name: "additional field of invalid type",
+			path: "/scores",
+			body: `{"name": "Elon", "math": "five"}`,
+			err:  &api.FieldTypeError{Field: "math", Type: "number"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   tc.path,
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPI_FindResponse_Discriminator(t *testing.T) {
+	a, err := parse.Parse("./testdata/discriminator.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "card",
+			body: `{"type": "card", "number": "4242424242424242"}`,
+			err:  nil,
+		},
+		{
+			name: "card with invalid number",
+			body: `{"type": "card", "number": "4242"}`,
+			err:  &api.PatternError{Field: "number", Pattern: "^[0-9]{16}$"},
+		},
+		{
+			name: "card without number",
+			body: `{"type": "card", "iban": "DE89370400440532013000"}`,
+			err:  api.ErrEmptyRequireField,
+		},
+		{
+			name: "bank",
+			body: `{"type": "bank", "iban": "DE89370400440532013000"}`,
+			err:  nil,
+		},
+		{
+			name: "bank without iban",
+			body: `{"type": "bank", "number": "4242424242424242"}`,
+			err:  api.ErrEmptyRequireField,
+		},
+		{
+			name: "unknown type",
+			body: `{"type": "cash"}`,
+			err:  &api.EnumError{Field: "type", Value: "cash"},
+		},
+		{
+			name: "without type",
+			body: `{"number": "4242424242424242"}`,
+			err:  api.ErrEmptyRequireField,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/payments",
+				Method: http.MethodPost,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPI_FindResponse_ValidateAnyMethodBody(t *testing.T) {
+	a, err := parse.Parse("./testdata/get-with-body.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		validate bool
+		body     string
+		err      error
+	}{
+		{
+			name:     "not validated: empty body",
+			validate: false,
+			body:     "",
+			err:      nil,
+		},
+		{
+			name:     "not validated: body without required field",
+			validate: false,
+			body:     `{"limit": 10}`,
+			err:      nil,
+		},
+		{
+			name:     "validated: empty body",
+			validate: true,
+			body:     "",
+			err:      api.ErrEmptyRequireField,
+		},
+		{
+			name:     "validated: body without required field",
+			validate: true,
+			body:     `{"limit": 10}`,
+			err:      api.ErrEmptyRequireField,
+		},
+		{
+			name:     "validated: body with required field",
+			validate: true,
+			body:     `{"query": "Elon"}`,
+			err:      nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a.ValidateAnyMethodBody = tc.validate
+
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/search",
+				Method: http.MethodGet,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, got.StatusCode)
+		})
+	}
+}
+
+func TestAPI_FindResponse_Validation(t *testing.T) {
+	a, err := parse.Parse("./testdata/get-with-body.yml")
+	require.NoError(t, err)
+
+	a.ValidateAnyMethodBody = true
+
+	tests := []struct {
+		name string
+		mode api.ValidationMode
+		err  error
+		log  string
+	}{
+		{
+			name: "strict",
+			mode: api.ValidationStrict,
+			err:  api.ErrEmptyRequireField,
+			log:  "",
+		},
+		{
+			name: "warn",
+			mode: api.ValidationWarn,
+			err:  nil,
+			log:  "empty require field",
+		},
+		{
+			name: "off",
+			mode: api.ValidationOff,
+			err:  nil,
+			log:  "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			l := zerolog.New(&buf)
+
+			a.Validation = tc.mode
+			a.Logger = &logger.Logger{Logger: &l}
+
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/search",
+				Method: http.MethodGet,
+				Body:   io.NopCloser(strings.NewReader(`{"limit": 10}`)),
+			})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, got.StatusCode)
+			}
+
+			if tc.log == "" {
+				require.Empty(t, buf.String())
+			} else {
+				require.Contains(t, buf.String(), tc.log)
+			}
+		})
+	}
+}
+
+func TestAPI_FindResponse_Validation_MalformedBody(t *testing.T) {
+	a, err := parse.Parse("./testdata/get-with-body.yml")
+	require.NoError(t, err)
+
+	a.ValidateAnyMethodBody = true
+
+	tests := []struct {
+		name    string
+		mode    api.ValidationMode
+		invalid bool
+		log     string
+	}{
+		{
+			name:    "strict",
+			mode:    api.ValidationStrict,
+			invalid: true,
+			log:     "",
+		},
+		{
+			name:    "warn",
+			mode:    api.ValidationWarn,
+			invalid: false,
+			log:     "malformed request body",
+		},
+		{
+			name:    "off",
+			mode:    api.ValidationOff,
+			invalid: false,
+			log:     "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			l := zerolog.New(&buf)
+
+			a.Validation = tc.mode
+			a.Logger = &logger.Logger{Logger: &l}
+
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/search",
+				Method: http.MethodGet,
+				Body:   io.NopCloser(strings.NewReader(`{"limit":`)),
+			})
+			if tc.invalid {
+				var validationError *api.ValidationError
+
+				require.ErrorAs(t, err, &validationError)
+				require.Equal(t, api.CodeMalformedBody, validationError.Code)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, got.StatusCode)
+			}
+
+			if tc.log == "" {
+				require.Empty(t, buf.String())
+			} else {
+				require.Contains(t, buf.String(), tc.log)
+			}
+		})
+	}
+}
+
+func TestAPI_FindOperation_EquallySpecific(t *testing.T) {
+	byID := api.Operation{Method: http.MethodGet, Path: "/items/{id}"}
+	bySlug := api.Operation{Method: http.MethodGet, Path: "/items/{slug}"}
+
+	for _, operations := range [][]api.Operation{{byID, bySlug}, {bySlug, byID}} {
+		a := api.API{Operations: operations}
+
+		got, ok := a.FindOperation(http.MethodGet, "/items/5")
+		require.True(t, ok)
+		require.Equal(t, "/items/{id}", got.Path)
+	}
+}
+
+func TestAPI_FindResponse_NoResponses(t *testing.T) {
+	a, err := parse.ParseBytes([]byte(`
+openapi: 3.0.3
+info:
+  title: No responses dummy API
+  version: 0.1.0
+paths:
+  /users:
+    get:
+      responses: {}
+`))
+	require.NoError(t, err)
+
+	_, err = a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: http.MethodGet,
+	})
+	require.Equal(t, &api.StatusCodeError{StatusCode: http.StatusOK}, err)
+}
+
+func TestAPI_FindResponse_OptionalSegment(t *testing.T) {
+	a := api.API{
+		Operations: []api.Operation{
+			{
+				Method: http.MethodGet,
+				Path:   "/items/{id?}",
+				Responses: []api.Response{
+					{StatusCode: http.StatusOK},
+				},
+			},
+		},
+	}
+
+	for _, path := range []string{"/items", "/items/5"} {
+		t.Run(path, func(t *testing.T) {
+			operation, ok := a.FindOperation(http.MethodGet, path)
+
+			require.True(t, ok)
+			require.Equal(t, "/items/{id?}", operation.Path)
+		})
+	}
+}
+
+func TestAPI_FindResponse_Query(t *testing.T) {
+	a, err := parse.Parse("./testdata/query.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		path  string
+		query url.Values
+		want  interface{}
+	}{
+		{
+			name:  "user",
+			path:  "/search",
+			query: url.Values{"type": {"user"}},
+			want:  map[string]interface{}{"kind": "user"},
+		},
+		{
+			name:  "org",
+			path:  "/search",
+			query: url.Values{"type": {"org"}},
+			want:  map[string]interface{}{"kind": "org"},
+		},
+		{
+			name:  "fallback: unknown query value",
+			path:  "/search",
+			query: url.Values{"type": {"repo"}},
+			want:  map[string]interface{}{"kind": "any"},
+		},
+		{
+			name:  "fallback: without query",
+			path:  "/search",
+			query: nil,
+			want:  map[string]interface{}{"kind": "any"},
+		},
+		{
+			name:  "required query parameter",
+			path:  "/users",
+			query: url.Values{"page": {"2"}},
+			want:  map[string]interface{}{"kind": "page"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   tc.path,
+				Method: http.MethodGet,
+				Query:  tc.query,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.ExampleValue(""))
+		})
+	}
+}
+
+func TestAPI_FindResponse_QueryParams(t *testing.T) {
+	a, err := parse.Parse("./testdata/query.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		path  string
+		query url.Values
+		want  interface{}
+		code  string
+	}{
+		{
+			name:  "query values",
+			path:  "/orders",
+			query: url.Values{"status": {"open"}, "limit": {"5"}},
+			want:  map[string]interface{}{"status": "open", "limit": float64(5)},
+		},
+		{
+			name:  "array parameter",
+			path:  "/orders",
+			query: url.Values{"status": {"open"}, "ids": {"1,2"}},
+			want:  map[string]interface{}{"status": "open", "limit": "{{ query.limit }}"},
+		},
+		{
+			name:  "optional parameter is absent",
+			path:  "/orders",
+			query: url.Values{"status": {"closed"}},
+			want:  map[string]interface{}{"status": "closed", "limit": "{{ query.limit }}"},
+		},
+		{
+			name:  "required parameter is absent",
+			path:  "/orders",
+			query: url.Values{"limit": {"5"}},
+			code:  api.CodeRequiredFieldMissing,
+		},
+		{
+			name:  "required parameter of query selector is absent",
+			path:  "/users",
+			query: nil,
+			code:  api.CodeRequiredFieldMissing,
+		},
+		{
+			name:  "not in enum",
+			path:  "/orders",
+			query: url.Values{"status": {"draft"}},
+			code:  api.CodeNotInEnum,
+		},
+		{
+			name:  "invalid type",
+			path:  "/orders",
+			query: url.Values{"status": {"open"}, "limit": {"five"}},
+			code:  api.CodeInvalidType,
+		},
+		{
+			name:  "invalid type of array item",
+			path:  "/orders",
+			query: url.Values{"status": {"open"}, "ids": {"1,two"}},
+			code:  api.CodeInvalidType,
+		},
+		{
+			name:  "out of range",
+			path:  "/orders",
+			query: url.Values{"status": {"open"}, "limit": {"101"}},
+			code:  api.CodeOutOfRange,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   tc.path,
+				Method: http.MethodGet,
+				Query:  tc.query,
+			})
+
+			if tc.code != "" {
+				var validationError *api.ValidationError
+
+				require.ErrorAs(t, err, &validationError)
+				require.Equal(t, tc.code, validationError.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.ExampleValue(""))
+		})
+	}
+
+	a.Validation = api.ValidationOff
+
+	_, err = a.FindResponse(api.FindResponseParams{Path: "/orders", Method: http.MethodGet})
+	require.NoError(t, err)
+}
+
+func TestAPI_FindResponse_HeaderCookieParams(t *testing.T) {
+	a, err := parse.Parse("./testdata/header-cookie-params.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   interface{}
+		field  string
+		code   string
+	}{
+		{
+			name: "values",
+			header: http.Header{
+				"X-Request-Id": {"42"},
+				"X-Retry":      {"3"},
+				"Cookie":       {"session=abcdef"},
+				"User-Agent":   {"curl"},
+			},
+			want: map[string]interface{}{
+				"requestId": "42",
+				"retry":     float64(3),
+				"session":   "abcdef",
+				"agent":     "curl",
+			},
+		},
+		{
+			name:   "required header is absent",
+			header: http.Header{"Cookie": {"session=abcdef"}},
+			field:  "X-Request-Id",
+			code:   api.CodeRequiredFieldMissing,
+		},
+		{
+			name:   "invalid header type",
+			header: http.Header{"X-Request-Id": {"42"}, "X-Retry": {"soon"}, "Cookie": {"session=abcdef"}},
+			field:  "X-Retry",
+			code:   api.CodeInvalidType,
+		},
+		{
+			name:   "required cookie is absent",
+			header: http.Header{"X-Request-Id": {"42"}},
+			field:  "session",
+			code:   api.CodeRequiredFieldMissing,
+		},
+		{
+			name:   "invalid cookie",
+			header: http.Header{"X-Request-Id": {"42"}, "Cookie": {"session=abc"}},
+			field:  "session",
+			code:   api.CodeInvalidLength,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/session",
+				Method: http.MethodGet,
+				Header: tc.header,
+			})
+
+			if tc.code != "" {
+				var validationError *api.ValidationError
+
+				require.ErrorAs(t, err, &validationError)
+				require.Equal(t, tc.field, validationError.Field)
+				require.Equal(t, tc.code, validationError.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.ExampleValue(""))
+		})
+	}
+}
+
+func TestAPI_FindOperation_Router(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users/{id}":             {Get: &openapi.Operation{}},
+				"/users/me":               {Get: &openapi.Operation{}},
+				"/users/{id}/posts":       {Get: &openapi.Operation{}},
+				"/orgs/{org}/users/{id}":  {Get: &openapi.Operation{}},
+				"/orgs/{org}/users/admin": {Delete: &openapi.Operation{}},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "param segment",
+			method: http.MethodGet,
+			path:   "/users/42",
+			want:   "/users/{id}",
+			ok:     true,
+		},
+		{
+			name:   "static segment wins over param segment",
+			method: http.MethodGet,
+			path:   "/users/me",
+			want:   "/users/me",
+			ok:     true,
+		},
+		{
+			name:   "nested param segment",
+			method: http.MethodGet,
+			path:   "/users/42/posts",
+			want:   "/users/{id}/posts",
+			ok:     true,
+		},
+		{
+			name:   "param segment when static segment has no method",
+			method: http.MethodGet,
+			path:   "/orgs/neotoolkit/users/admin",
+			want:   "/orgs/{org}/users/{id}",
+			ok:     true,
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/users/42/comments",
+			ok:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := a.FindOperation(tc.method, tc.path)
+
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.want, got.Path)
+		})
+	}
+}
+
+func TestAPI_FindOperation_NormalizePath(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/":             {Get: &openapi.Operation{}},
+				"/users/":       {Get: &openapi.Operation{}},
+				"//users//{id}": {Get: &openapi.Operation{}},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "root",
+			path: "/",
+			want: "/",
+		},
+		{
+			name: "empty path",
+			path: "",
+			want: "/",
+		},
+		{
+			name: "without trailing slash",
+			path: "/users",
+			want: "/users",
+		},
+		{
+			name: "trailing slash",
+			path: "/users/",
+			want: "/users",
+		},
+		{
+			name: "duplicate slashes",
+			path: "//users",
+			want: "/users",
+		},
+		{
+			name: "duplicate slashes between segments",
+			path: "/users//42//",
+			want: "/users/{id}",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := a.FindOperation(http.MethodGet, tc.path)
+			require.True(t, ok)
+			require.Equal(t, tc.want, got.Path)
+
+			// linear scan matches the same operation
+			got, ok = api.API{Operations: a.Operations}.FindOperation(http.MethodGet, tc.path)
+			require.True(t, ok)
+			require.Equal(t, tc.want, got.Path)
+		})
+	}
+}
+
+func TestAPI_Methods(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users":      {Get: &openapi.Operation{}, Post: &openapi.Operation{}},
+				"/users/{id}": {Put: &openapi.Operation{}, Options: &openapi.Operation{}},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"GET", "HEAD", "OPTIONS", "POST"}, a.Methods("/users/"))
+	require.Equal(t, []string{"OPTIONS", "PUT"}, a.Methods("/users/42"))
+	require.Nil(t, a.Methods("/orgs"))
+
+	// HEAD request matches GET operation
+	got, ok := a.FindOperation(http.MethodHead, "/users")
+	require.True(t, ok)
+	require.Equal(t, http.MethodGet, got.Method)
+
+	_, ok = a.FindOperation(http.MethodHead, "/users/42")
+	require.False(t, ok)
+}
+
+func TestAPI_FindResponse_MethodNotAllowed(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users": {
+					Get:  &openapi.Operation{Responses: openapi.Responses{"200": &openapi.Response{}}},
+					Post: &openapi.Operation{Responses: openapi.Responses{"201": &openapi.Response{}}},
+				},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	_, err = a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: http.MethodDelete,
+	})
+	require.ErrorIs(t, err, api.ErrMethodNotAllowed)
+	require.EqualError(t, err, "method not allowed: DELETE /users")
+
+	_, err = a.FindResponse(api.FindResponseParams{
+		Path:   "/orgs",
+		Method: http.MethodDelete,
+	})
+	require.EqualError(t, err, (&api.FindResponseError{Method: http.MethodDelete, Path: "/orgs"}).Error())
+}
+
+func TestAPI_FindOperation_MethodCase(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/users": {
+					Get: &openapi.Operation{},
+					Post: &openapi.Operation{
+						Responses: openapi.Responses{"201": &openapi.Response{}},
+					},
+				},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{method: "post", want: http.MethodPost},
+		{method: "Post", want: http.MethodPost},
+		{method: "get", want: http.MethodGet},
+		{method: "head", want: http.MethodGet},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method, func(t *testing.T) {
+			got, ok := a.FindOperation(tc.method, "/users")
+			require.True(t, ok)
+			require.Equal(t, tc.want, got.Method)
+
+			got, ok = api.API{Operations: a.Operations}.FindOperation(tc.method, "/users")
+			require.True(t, ok)
+			require.Equal(t, tc.want, got.Method)
+		})
+	}
+
+	resp, err := a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: "post",
+		Body:   io.NopCloser(strings.NewReader(`{}`)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestAPI_FindOperation_CatchAll(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/files/{path...}":  {Get: &openapi.Operation{}},
+				"/files/{id}/meta":  {Get: &openapi.Operation{}},
+				"/files/readme.md":  {Get: &openapi.Operation{}},
+				"/files/{id}":       {Put: &openapi.Operation{}},
+				"/proxy/{proxy+}":   {Post: &openapi.Operation{}},
+				"/proxy/{id}/state": {Post: &openapi.Operation{}},
+			},
+		},
+	}
+
+	tree, err := b.Build()
+	require.NoError(t, err)
+
+	linear := api.API{Operations: tree.Operations}
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+		ok     bool
+	}{
+		{method: http.MethodGet, path: "/files/a/b/c", want: "/files/{path...}", ok: true},
+		{method: http.MethodGet, path: "/files/single", want: "/files/{path...}", ok: true},
+		{method: http.MethodGet, path: "/files/readme.md", want: "/files/readme.md", ok: true},
+		{method: http.MethodGet, path: "/files/42/meta", want: "/files/{id}/meta", ok: true},
+		{method: http.MethodPut, path: "/files/42", want: "/files/{id}", ok: true},
+		{method: http.MethodPost, path: "/proxy/42/state", want: "/proxy/{id}/state", ok: true},
+		{method: http.MethodPost, path: "/proxy/42/state/history", want: "/proxy/{proxy+}", ok: true},
+		{method: http.MethodGet, path: "/files", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			for _, a := range []api.API{tree, linear} {
+				got, ok := a.FindOperation(tc.method, tc.path)
+				require.Equal(t, tc.ok, ok)
+				require.Equal(t, tc.want, got.Path)
+			}
+		})
+	}
+}
+
+func TestAPI_FindOperation_PathParamConstraints(t *testing.T) {
+	tree, err := parse.Parse("./testdata/path-params.yml")
+	require.NoError(t, err)
+
+	linear := api.API{Operations: tree.Operations}
+
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{path: "/orders/42", want: "/orders/{id}", ok: true},
+		{path: "/orders/latest", want: "/orders/latest", ok: true},
+		{path: "/orders/first", ok: false},
+		{path: "/orders/4.2", ok: false},
+		{path: "/users/elon", want: "/users/{username}", ok: true},
+		{path: "/users/Elon", want: "/users/{path...}", ok: true},
+		{path: "/users/elon/orders", want: "/users/{path...}", ok: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			for _, a := range []api.API{tree, linear} {
+				got, ok := a.FindOperation(http.MethodGet, tc.path)
+				require.Equal(t, tc.ok, ok)
+				require.Equal(t, tc.want, got.Path)
+			}
+		})
+	}
+}
+
+func TestAPI_FindOperation_Specificity(t *testing.T) {
+	me := api.Operation{Method: http.MethodGet, Path: "/users/me"}
+	user := api.Operation{Method: http.MethodGet, Path: "/users/{id}"}
+
+	tests := []struct {
+		name       string
+		operations []api.Operation
+	}{
+		{
+			name:       "static path first",
+			operations: []api.Operation{me, user},
+		},
+		{
+			name:       "parameterized path first",
+			operations: []api.Operation{user, me},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api.API{Operations: tc.operations}
+
+			got, ok := a.FindOperation(http.MethodGet, "/users/me")
+			require.True(t, ok)
+			require.Equal(t, "/users/me", got.Path)
+
+			got, ok = a.FindOperation(http.MethodGet, "/users/42")
+			require.True(t, ok)
+			require.Equal(t, "/users/{id}", got.Path)
+		})
+	}
+}
+
+func BenchmarkAPI_FindOperation(b *testing.B) {
+	const count = 1000
+
+	paths := make(openapi.Paths, count)
+	for i := 0; i < count; i++ {
+		paths[fmt.Sprintf("/resources%d/{id}/items/{item}", i)] = &openapi.Path{Get: &openapi.Operation{}}
+	}
+
+	builder := api.Builder{OpenAPI: openapi.OpenAPI{Paths: paths}}
+
+	tree, err := builder.Build()
+	require.NoError(b, err)
+
+	linear := api.API{Operations: tree.Operations}
+	path := fmt.Sprintf("/resources%d/1/items/2", count-1)
+
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			linear.FindOperation(http.MethodGet, path)
+		}
+	})
+
+	b.Run("tree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree.FindOperation(http.MethodGet, path)
+		}
+	})
+}
+
+func TestAPI_FindResponse_Variant(t *testing.T) {
+	a, err := parse.Parse("./testdata/one-of.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		path    string
+		variant string
+		want    map[string]interface{}
+	}{
+		{
+			name:    "oneOf",
+			path:    "/pets/1",
+			variant: "Dog",
+			want:    map[string]interface{}{"kind": "dog", "goodBoy": true},
+		},
+		{
+			name:    "anyOf",
+			path:    "/owners/1/pet",
+			variant: "Cat",
+			want:    map[string]interface{}{"name": "Elon", "kind": "cat", "lives": int64(9)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:    tc.path,
+				Method:  http.MethodGet,
+				Variant: tc.variant,
+			})
+			require.NoError(t, err)
+			require.Len(t, got.Variants, 2)
+			require.Equal(t, tc.want, got.ExampleValue(""))
+		})
+	}
+
+	t.Run("unknown variant", func(t *testing.T) {
+		got, err := a.FindResponse(api.FindResponseParams{
+			Path:    "/pets/1",
+			Method:  http.MethodGet,
+			Variant: "Bird",
+		})
+		require.NoError(t, err)
+		require.Contains(t, []interface{}{
+			map[string]interface{}{"kind": "cat", "lives": int64(9)},
+			map[string]interface{}{"kind": "dog", "goodBoy": true},
+		}, got.ExampleValue(""))
+	})
+}
+
+func TestAPI_FindResponse_StatusCodeRange(t *testing.T) {
+	a, err := parse.Parse("./testdata/status-ranges.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		prefer     int
+		statusCode int
+		want       interface{}
+		err        error
+	}{
+		{
+			name:       "specific response",
+			method:     http.MethodGet,
+			path:       "/users/1",
+			prefer:     0,
+			statusCode: http.StatusOK,
+			want:       map[string]interface{}{"firstName": "Elon"},
+			err:        nil,
+		},
+		{
+			name:       "specific response within range",
+			method:     http.MethodGet,
+			path:       "/users/1",
+			prefer:     http.StatusNotFound,
+			statusCode: http.StatusNotFound,
+			want:       map[string]interface{}{"message": "user not found"},
+			err:        nil,
+		},
+		{
+			name:       "range response",
+			method:     http.MethodGet,
+			path:       "/users/1",
+			prefer:     http.StatusTooManyRequests,
+			statusCode: http.StatusTooManyRequests,
+			want:       map[string]interface{}{"message": "client error"},
+			err:        nil,
+		},
+		{
+			name:       "out of range",
+			method:     http.MethodGet,
+			path:       "/users/1",
+			prefer:     http.StatusServiceUnavailable,
+			statusCode: 0,
+			want:       nil,
+			err:        &api.StatusCodeError{StatusCode: http.StatusServiceUnavailable},
+		},
+		{
+			name:       "first status code of range",
+			method:     http.MethodPost,
+			path:       "/users",
+			prefer:     0,
+			statusCode: http.StatusOK,
+			want:       nil,
+			err:        nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:             tc.path,
+				Method:           tc.method,
+				PreferStatusCode: tc.prefer,
+			})
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.statusCode, got.StatusCode)
+			require.Equal(t, tc.want, got.ExampleValue(""))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+//nolint:gochecknoglobals // uuidPattern matches UUID of any version in canonical form
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat reports whether string value has format of schema, values of unknown formats are valid
+func validFormat(format, value string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(value)
+
+		return err == nil && addr.Address == value
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "uri":
+		u, err := url.Parse(value)
+
+		return err == nil && u.Scheme != ""
+	case "ipv4":
+		ip := net.ParseIP(value)
+
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(value)
+
+		return ip != nil && ip.To4() == nil
+	default:
+		return true
+	}
+}
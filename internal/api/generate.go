@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neotoolkit/faker"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// Clock is source of current time for generated values
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock -.
+type SystemClock struct{}
+
+// Now -.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockFunc is adapter of function to Clock, e.g. of frozen time in tests
+type ClockFunc func() time.Time
+
+// Now -.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// now returns current time of clock, SystemClock is used when clock is nil
+func now(clock Clock) time.Time {
+	if nil == clock {
+		return time.Now()
+	}
+
+	return clock.Now()
+}
+
+//nolint:gochecknoglobals // word lists for generated values faker has no generators for
+var (
+	cities    = []string{"London", "Paris", "Berlin", "Tokyo", "Moscow", "New York", "Toronto", "Sydney"}
+	countries = []string{"United Kingdom", "France", "Germany", "Japan", "Russia", "United States", "Canada", "Australia"}
+	streets   = []string{"Main Street", "High Street", "Park Avenue", "Baker Street", "Broadway", "Elm Street"}
+	words     = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit"}
+)
+
+// formatFakers are names of faker generators of string formats
+//
+//nolint:gochecknoglobals // lookup table of formats faker has generators for
+var formatFakers = map[string]string{
+	"email":    "email",
+	"hostname": "domain",
+	"uuid":     "uuid",
+}
+
+// faker returns Faker of builder producing values by Rand, so they are reproducible by its seed.
+// Faker is created on first use when it is not set.
+func (b *Builder) faker() faker.Faker {
+	if nil == b.Faker.Generator {
+		b.Faker = faker.NewFaker()
+	}
+
+	if b.Rand != nil {
+		b.Faker.Generator = b.Rand
+	}
+
+	return b.Faker
+}
+
+// generateString returns value for string schema without example matching pattern,
+// otherwise based on format, with length constraints of schema
+func (b *Builder) generateString(s openapi.Schema) string {
+	if s.Pattern != "" {
+		if val, ok := b.generatePattern(s); ok {
+			return val
+		}
+	}
+
+	return fitLength(b.formatString(s), s)
+}
+
+// formatString returns value for string schema based on format, faker generators are used for formats it knows
+func (b *Builder) formatString(s openapi.Schema) string {
+	f := b.faker()
+
+	if name, ok := formatFakers[s.Format]; ok {
+		return f.ByName(name).(string)
+	}
+
+	switch s.Format {
+	case "date":
+		return now(b.Clock).UTC().Format("2006-01-02")
+	case "date-time":
+		return now(b.Clock).UTC().Format(time.RFC3339)
+	case "uri", "url":
+		return generateURL(f).(string)
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", f.IntBetween(1, 254))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", f.IntBetween(1, 0xfffe))
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(f.RandomStringElement(words)))
+	default:
+		return f.RandomStringElement(words)
+	}
+}
+
+// pickEnum returns enum member, random one when Rand is set, otherwise first one
+func (b *Builder) pickEnum(enum []interface{}) interface{} {
+	if nil == b.Rand {
+		return enum[0]
+	}
+
+	return enum[b.Rand.Intn(len(enum))]
+}
+
+// generateNull reports whether value of nullable schema without example is generated as null,
+// one of nullChance values is null
+func (b *Builder) generateNull(s openapi.Schema) bool {
+	const nullChance = 10
+
+	return s.Nullable && b.Rand.Intn(nullChance) == 0
+}
+
+// generateBool returns value for boolean schema without example
+func (b *Builder) generateBool() bool {
+	return b.Rand.Intn(2) == 1
+}
+
+// generateItems returns elements of array schema without example, count of elements is
+// three bounded by minItems and maxItems. First element is example of converted items schema,
+// other elements are generated by converting items schema again. Elements of large arrays
+// are generated on demand, see itemGenerator.
+func (b *Builder) generateItems(s openapi.Schema, first Schema) (Items, error) {
+	const defaultCount = 3
+
+	count := defaultCount
+	if s.MaxItems > 0 && count > s.MaxItems {
+		count = s.MaxItems
+	}
+
+	if count < s.MinItems {
+		count = s.MinItems
+	}
+
+	if count >= streamMinItems {
+		return Items{Count: count, Generate: b.itemGenerator(*s.Items, first.ExampleValue())}, nil
+	}
+
+	pool := make([]interface{}, 0, count)
+
+	for i := 0; i < count; i++ {
+		item := first
+
+		if i > 0 {
+			var err error
+
+			item, err = b.convertSchema(*s.Items)
+			if err != nil {
+				return Items{}, err
+			}
+		}
+
+		pool = append(pool, item.ExampleValue())
+	}
+
+	return Items{Count: count, Pool: pool}, nil
+}
+
+// itemGenerator returns generator of elements of items schema, first element is example of first.
+// Other elements are generated from items schema with random generator seeded by index of element,
+// so element is same whenever it is generated. Elements are generated one at a time, since builder
+// caches are not concurrency-safe.
+func (b *Builder) itemGenerator(items openapi.Schema, first interface{}) func(i int) interface{} {
+	seed := b.Rand.Int63()
+
+	g := *b
+	g.Faker = b.faker()
+	g.Operations, g.refs, g.mismatches = nil, nil, nil
+
+	var mu sync.Mutex
+
+	return func(i int) interface{} {
+		if i == 0 {
+			return first
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		g.Rand = rand.New(rand.NewSource(seed + int64(i)))
+
+		s, err := g.convertSchema(items)
+		if err != nil {
+			return nil
+		}
+
+		return s.ExampleValue()
+	}
+}
+
+// generator returns value for schema without example by faker
+type generator func(f faker.Faker) interface{}
+
+// inferByName returns generator based on property name, e.g. `email` or `billingEmail` generates email
+func inferByName(name string) (generator, bool) {
+	n := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+
+	switch {
+	case strings.Contains(n, "email"):
+		return generateEmail, true
+	case strings.Contains(n, "firstname"):
+		return generateFirstName, true
+	case strings.Contains(n, "lastname"), strings.Contains(n, "surname"):
+		return generateLastName, true
+	case n == "name", strings.HasSuffix(n, "fullname"):
+		return generateFullName, true
+	case strings.Contains(n, "phone"):
+		return generatePhone, true
+	case strings.Contains(n, "city"):
+		return generateCity, true
+	case strings.Contains(n, "country"):
+		return generateCountry, true
+	case strings.Contains(n, "street"):
+		return generateStreet, true
+	case strings.HasSuffix(n, "url"), strings.HasSuffix(n, "uri"), strings.Contains(n, "website"):
+		return generateURL, true
+	case n == "id", n == "uuid", strings.HasSuffix(n, "uuid"):
+		return generateUUID, true
+	default:
+		return nil, false
+	}
+}
+
+func generateFirstName(f faker.Faker) interface{} {
+	return f.Person().FirstName()
+}
+
+func generateLastName(f faker.Faker) interface{} {
+	return f.Person().LastName()
+}
+
+func generateFullName(f faker.Faker) interface{} {
+	return f.Person().Name()
+}
+
+func generateEmail(f faker.Faker) interface{} {
+	return f.Internet().Email()
+}
+
+func generatePhone(f faker.Faker) interface{} {
+	return fmt.Sprintf("+1-%03d-%03d-%04d", f.IntBetween(0, 999), f.IntBetween(0, 999), f.IntBetween(0, 9999))
+}
+
+func generateCity(f faker.Faker) interface{} {
+	return f.RandomStringElement(cities)
+}
+
+func generateCountry(f faker.Faker) interface{} {
+	return f.RandomStringElement(countries)
+}
+
+func generateStreet(f faker.Faker) interface{} {
+	return fmt.Sprintf("%d %s", f.IntBetween(1, 200), f.RandomStringElement(streets))
+}
+
+// generateURL returns URL of fake domain with path of fake username
+func generateURL(f faker.Faker) interface{} {
+	return "https://" + f.Internet().Domain() + "/" + strings.ToLower(f.Internet().Username())
+}
+
+func generateUUID(f faker.Faker) interface{} {
+	return f.UUID().V4()
+}
@@ -0,0 +1,565 @@
+package api_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/openapi"
+	"github.com/neotoolkit/dummy/internal/parse"
+)
+
+func TestBuilder_Build_InferByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		property string
+		pattern  string
+	}{
+		{
+			name:     "email",
+			property: "billingEmail",
+			pattern:  `^[a-z']+(\.[a-z']+)?@[a-z]{3}\.[a-z]+$`,
+		},
+		{
+			name:     "first name",
+			property: "first_name",
+			pattern:  `^[A-Z][a-z]+$`,
+		},
+		{
+			name:     "uuid",
+			property: "id",
+			pattern:  `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		},
+		{
+			name:     "phone",
+			property: "phoneNumber",
+			pattern:  `^\+1-\d{3}-\d{3}-\d{4}$`,
+		},
+		{
+			name:     "not inferred",
+			property: "comment",
+			pattern:  `^[a-z]+$`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := api.Builder{
+				OpenAPI: openapi.OpenAPI{
+					Paths: openapi.Paths{
+						"/test": {
+							Get: &openapi.Operation{
+								Responses: openapi.Responses{
+									"200": {
+										Content: openapi.Content{
+											"application/json": {
+												Schema: openapi.Schema{
+													Type: "object",
+													Properties: openapi.Schemas{
+														tc.property: {Type: "string"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Rand: api.NewRand(1),
+			}
+
+			a, err := b.Build()
+			require.NoError(t, err)
+
+			got, ok := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+			require.True(t, ok)
+			require.Regexp(t, regexp.MustCompile(tc.pattern), got[tc.property])
+		})
+	}
+}
+
+func TestBuilder_Build_Generate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema openapi.Schema
+		check  func(t *testing.T, got interface{})
+	}{
+		{
+			name:   "string",
+			schema: openapi.Schema{Type: "string"},
+			check: func(t *testing.T, got interface{}) {
+				require.Regexp(t, `^[a-z]+$`, got)
+			},
+		},
+		{
+			name:   "string, email format",
+			schema: openapi.Schema{Type: "string", Format: "email"},
+			check: func(t *testing.T, got interface{}) {
+				email, ok := got.(string)
+				require.True(t, ok)
+
+				address, err := mail.ParseAddress(email)
+				require.NoError(t, err)
+				require.Equal(t, email, address.Address)
+			},
+		},
+		{
+			name:   "string, explicit example",
+			schema: openapi.Schema{Type: "string", Format: "email", Example: "elon@example.com"},
+			check: func(t *testing.T, got interface{}) {
+				require.Equal(t, "elon@example.com", got)
+			},
+		},
+		{
+			name:   "integer",
+			schema: openapi.Schema{Type: "integer"},
+			check: func(t *testing.T, got interface{}) {
+				require.IsType(t, int64(0), got)
+			},
+		},
+		{
+			name:   "number",
+			schema: openapi.Schema{Type: "number"},
+			check: func(t *testing.T, got interface{}) {
+				require.IsType(t, float64(0), got)
+			},
+		},
+		{
+			name:   "boolean, explicit example",
+			schema: openapi.Schema{Type: "boolean", Example: true},
+			check: func(t *testing.T, got interface{}) {
+				require.Equal(t, true, got)
+			},
+		},
+		{
+			name:   "array, minItems and maxItems",
+			schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "integer"}, MinItems: 2, MaxItems: 2},
+			check: func(t *testing.T, got interface{}) {
+				require.Len(t, got, 2)
+				require.IsType(t, int64(0), got.([]interface{})[0])
+			},
+		},
+		{
+			name:   "array without bounds",
+			schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+			check: func(t *testing.T, got interface{}) {
+				require.Len(t, got, 3)
+			},
+		},
+		{
+			name:   "array, maxItems",
+			schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}, MaxItems: 1},
+			check: func(t *testing.T, got interface{}) {
+				require.Len(t, got, 1)
+			},
+		},
+		{
+			name:   "array, minItems",
+			schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}, MinItems: 5},
+			check: func(t *testing.T, got interface{}) {
+				require.Len(t, got, 5)
+			},
+		},
+		{
+			name:   "array, explicit example",
+			schema: openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}, Example: []interface{}{"a"}},
+			check: func(t *testing.T, got interface{}) {
+				require.Equal(t, []interface{}{"a"}, got)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := api.Builder{
+				OpenAPI: openapi.OpenAPI{
+					Paths: openapi.Paths{
+						"/test": {
+							Get: &openapi.Operation{
+								Responses: openapi.Responses{
+									"200": {
+										Content: openapi.Content{
+											"application/json": {
+												Schema: tc.schema,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Rand: api.NewRand(1),
+			}
+
+			a, err := b.Build()
+			require.NoError(t, err)
+
+			tc.check(t, a.Operations[0].Responses[0].ExampleValue(""))
+		})
+	}
+}
+
+func TestBuilder_Build_StringConstraints(t *testing.T) {
+	a, err := parse.ParseWithOptions("./testdata/string.yml", parse.WithSeed(1))
+	require.NoError(t, err)
+
+	got, ok := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+	require.True(t, ok)
+
+	require.Regexp(t, `^[A-Z]{3}-[0-9]{2}$`, got["code"])
+	require.GreaterOrEqual(t, utf8.RuneCountInString(got["name"].(string)), 2)
+	require.LessOrEqual(t, utf8.RuneCountInString(got["name"].(string)), 5)
+	require.GreaterOrEqual(t, utf8.RuneCountInString(got["description"].(string)), 20)
+	require.Equal(t, "", got["empty"])
+}
+
+func TestBuilder_Build_Pattern(t *testing.T) {
+	one := 1
+
+	tests := []struct {
+		name   string
+		schema openapi.Schema
+	}{
+		{
+			name:   "anchors",
+			schema: openapi.Schema{Type: "string", Pattern: `^\d{4}-[a-f]+$`},
+		},
+		{
+			name:   "alternation and groups",
+			schema: openapi.Schema{Type: "string", Pattern: `^(red|green|blue)(-[0-9]{1,3})?$`},
+		},
+		{
+			name:   "negated class and any character",
+			schema: openapi.Schema{Type: "string", Pattern: `^[^0-9]+.x*$`},
+		},
+		{
+			name:   "with length",
+			schema: openapi.Schema{Type: "string", Pattern: `^[a-z]*$`, MaxLength: &one},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := api.Builder{
+				OpenAPI: openapi.OpenAPI{
+					Paths: openapi.Paths{
+						"/test": {
+							Get: &openapi.Operation{
+								Responses: openapi.Responses{
+									"200": {
+										Content: openapi.Content{
+											"application/json": {
+												Schema: tc.schema,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Rand: api.NewRand(1),
+			}
+
+			a, err := b.Build()
+			require.NoError(t, err)
+
+			got, ok := a.Operations[0].Responses[0].ExampleValue("").(string)
+			require.True(t, ok)
+			require.Regexp(t, tc.schema.Pattern, got)
+
+			if tc.schema.MaxLength != nil {
+				require.LessOrEqual(t, len(got), *tc.schema.MaxLength)
+			}
+		})
+	}
+}
+
+func TestBuilder_Build_Nullable(t *testing.T) {
+	a, err := parse.ParseWithOptions("./testdata/nullable.yml", parse.WithSeed(1))
+	require.NoError(t, err)
+
+	got := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+	require.IsType(t, "", got["nickname"])
+
+	// nullable field is occasionally generated as null, deterministically for seed
+	nulls := 0
+
+	for seed := int64(1); seed <= 100; seed++ {
+		a, err := parse.ParseWithOptions("./testdata/nullable.yml", parse.WithSeed(seed))
+		require.NoError(t, err)
+
+		again, err := parse.ParseWithOptions("./testdata/nullable.yml", parse.WithSeed(seed))
+		require.NoError(t, err)
+
+		value := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})["nickname"]
+		require.Equal(t, value, again.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})["nickname"])
+
+		if nil == value {
+			nulls++
+		}
+	}
+
+	require.Greater(t, nulls, 0)
+	require.Less(t, nulls, 50)
+}
+
+func TestBuilder_Build_NestedObject(t *testing.T) {
+	object := func(properties openapi.Schemas, example interface{}) *openapi.Schema {
+		return &openapi.Schema{Type: "object", Properties: properties, Example: example}
+	}
+
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/orders": {Get: &openapi.Operation{
+					Responses: openapi.Responses{"200": &openapi.Response{
+						Content: openapi.Content{"application/json": &openapi.MediaType{
+							Schema: *object(openapi.Schemas{
+								"total": {Type: "integer"},
+								"customer": object(openapi.Schemas{
+									"nickname": {Type: "string"},
+									"address": object(openapi.Schemas{
+										"zip": {Type: "string"},
+									}, nil),
+								}, nil),
+								"shipping": object(openapi.Schemas{
+									"carrier": {Type: "string"},
+									"days":    {Type: "integer"},
+								}, map[string]interface{}{"carrier": "DHL"}),
+							}, nil),
+						}},
+					}},
+				}},
+			},
+		},
+		Rand: api.NewRand(1),
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	got := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+	require.IsType(t, int64(0), got["total"])
+
+	customer := got["customer"].(map[string]interface{})
+	require.NotEmpty(t, customer["nickname"])
+
+	address := customer["address"].(map[string]interface{})
+	require.NotEmpty(t, address["zip"])
+
+	// explicit example is used as is, without generated properties
+	require.Equal(t, map[string]interface{}{"carrier": "DHL"}, got["shipping"])
+}
+
+func TestBuilder_Build_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		check  func(t *testing.T, got string)
+	}{
+		{
+			format: "uuid",
+			check: func(t *testing.T, got string) {
+				require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, got)
+
+				_, err := hex.DecodeString(strings.ReplaceAll(got, "-", ""))
+				require.NoError(t, err)
+			},
+		},
+		{
+			format: "date",
+			check: func(t *testing.T, got string) {
+				_, err := time.Parse("2006-01-02", got)
+				require.NoError(t, err)
+			},
+		},
+		{
+			format: "date-time",
+			check: func(t *testing.T, got string) {
+				_, err := time.Parse(time.RFC3339, got)
+				require.NoError(t, err)
+			},
+		},
+		{
+			format: "email",
+			check: func(t *testing.T, got string) {
+				_, err := mail.ParseAddress(got)
+				require.NoError(t, err)
+			},
+		},
+		{
+			format: "uri",
+			check: func(t *testing.T, got string) {
+				u, err := url.ParseRequestURI(got)
+				require.NoError(t, err)
+				require.Equal(t, "https", u.Scheme)
+			},
+		},
+		{
+			format: "ipv4",
+			check: func(t *testing.T, got string) {
+				ip := net.ParseIP(got)
+				require.NotNil(t, ip)
+				require.NotNil(t, ip.To4())
+			},
+		},
+		{
+			format: "ipv6",
+			check: func(t *testing.T, got string) {
+				ip := net.ParseIP(got)
+				require.NotNil(t, ip)
+				require.Nil(t, ip.To4())
+			},
+		},
+		{
+			format: "byte",
+			check: func(t *testing.T, got string) {
+				_, err := base64.StdEncoding.DecodeString(got)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			b := api.Builder{
+				OpenAPI: openapi.OpenAPI{
+					Paths: openapi.Paths{
+						"/test": {
+							Get: &openapi.Operation{
+								Responses: openapi.Responses{
+									"200": {
+										Content: openapi.Content{
+											"application/json": {
+												Schema: openapi.Schema{Type: "string", Format: tc.format},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Rand: api.NewRand(1),
+			}
+
+			a, err := b.Build()
+			require.NoError(t, err)
+
+			got, ok := a.Operations[0].Responses[0].ExampleValue("").(string)
+			require.True(t, ok)
+
+			tc.check(t, got)
+		})
+	}
+}
+
+type frozenClock struct {
+	now time.Time
+}
+
+func (c frozenClock) Now() time.Time {
+	return c.now
+}
+
+func TestBuilder_Build_Clock(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/test": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{
+							"200": {
+								Content: openapi.Content{
+									"application/json": {
+										Schema: openapi.Schema{
+											Type: "object",
+											Properties: openapi.Schemas{
+												"birthday":  {Type: "string", Format: "date"},
+												"createdAt": {Type: "string", Format: "date-time"},
+												"updatedAt": {Type: "string", Format: "date-time", Example: "2021-01-01T00:00:00Z"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Rand:  api.NewRand(1),
+		Clock: frozenClock{now: time.Date(2022, time.February, 24, 10, 30, 0, 0, time.UTC)},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"birthday":  "2022-02-24",
+		"createdAt": "2022-02-24T10:30:00Z",
+		"updatedAt": "2021-01-01T00:00:00Z",
+	}, a.Operations[0].Responses[0].ExampleValue(""))
+}
+
+func TestBuilder_Build_Discriminator(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		a, err := parse.ParseWithOptions("./testdata/discriminator.yml", parse.WithSeed(seed))
+		require.NoError(t, err)
+
+		example, ok := a.Operations[0].Responses[0].ExampleValue("").(map[string]interface{})
+		require.True(t, ok)
+
+		switch example["type"] {
+		case "card":
+			require.Contains(t, example, "number")
+		case "bank":
+			require.Contains(t, example, "iban")
+		default:
+			t.Fatalf("unexpected discriminator value %v", example["type"])
+		}
+	}
+}
+
+func TestBuilder_Build_Range(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		a, err := parse.ParseWithOptions("./testdata/range.yml", parse.WithSeed(seed))
+		require.NoError(t, err)
+
+		op, ok := a.FindOperation(http.MethodGet, "/scores")
+		require.True(t, ok)
+
+		example, ok := op.Responses[0].ExampleValue("").(map[string]interface{})
+		require.True(t, ok)
+
+		score, ok := example["score"].(int64)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, score, int64(10))
+		require.LessOrEqual(t, score, int64(20))
+
+		ratio, ok := example["ratio"].(float64)
+		require.True(t, ok)
+		require.Greater(t, ratio, 0.0)
+		require.Less(t, ratio, 1.0)
+
+		require.Equal(t, int64(2), example["level"])
+
+		debt, ok := example["debt"].(int64)
+		require.True(t, ok)
+		require.LessOrEqual(t, debt, int64(-100))
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// Holder holds API which may be replaced while requests are served, e.g. on specification change
+type Holder struct {
+	value atomic.Value
+}
+
+// NewHolder returns a new instance of Holder with API
+func NewHolder(a API) *Holder {
+	h := &Holder{}
+	h.Store(a)
+
+	return h
+}
+
+// Load returns current API
+func (h *Holder) Load() API {
+	a, _ := h.value.Load().(API)
+
+	return a
+}
+
+// Store replaces current API, requests being served keep API they loaded
+func (h *Holder) Store(a API) {
+	h.value.Store(a)
+}
+
+// WithOperations returns API with settings of a and operations of b, e.g. of reloaded specification
+func (a API) WithOperations(b API) API {
+	a.Operations, a.router = b.Operations, b.router
+
+	return a
+}
+
+// OperationConflictError -.
+type OperationConflictError struct {
+	Method string
+	Path   string
+}
+
+// Error -.
+func (e *OperationConflictError) Error() string {
+	return "conflicting operation " + e.Method + " " + e.Path
+}
+
+// Merge returns API with settings of a and operations of a and others, e.g. of specifications of several services.
+// Clock of a is kept, Clock of first of others is used when a has no one. Operations matching same requests,
+// i.e. of same method, path up to parameter names and query, are conflicting.
+func (a API) Merge(others ...API) (API, error) {
+	operations := append([]Operation(nil), a.Operations...)
+
+	seen := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		seen[op.conflictKey()] = true
+	}
+
+	for _, other := range others {
+		for _, op := range other.Operations {
+			key := op.conflictKey()
+			if seen[key] {
+				return API{}, &OperationConflictError{Method: op.Method, Path: op.Path}
+			}
+
+			seen[key] = true
+
+			operations = append(operations, op)
+		}
+	}
+
+	a.Operations, a.router = operations, newRouter(operations)
+
+	for _, other := range others {
+		if nil == a.Clock {
+			a.Clock = other.Clock
+		}
+	}
+
+	return a, nil
+}
+
+// conflictKey returns key of requests matched by operation: method, path with parameter names omitted,
+// e.g. `/users/{}` for `/users/{id}`, and sorted query
+func (o Operation) conflictKey() string {
+	segments := strings.Split(o.Path, "/")
+	for i, segment := range segments {
+		if isParam(segment) {
+			segments[i] = strings.Replace(segment, paramName(segment), "", 1)
+		}
+	}
+
+	query := make(url.Values, len(o.Query))
+	for name, value := range o.Query {
+		query.Set(name, value)
+	}
+
+	return o.Method + " " + strings.Join(segments, "/") + "?" + query.Encode()
+}
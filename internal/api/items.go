@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// streamMinItems is count of generated array elements from which array is not materialized
+const streamMinItems = 100
+
+// Items is generated array of Count elements. Elements are produced on demand, so large arrays,
+// e.g. of big `minItems`, are serialized without materializing them.
+type Items struct {
+	Count int
+	// Pool contains elements cycled through when Generate is nil, e.g. elements of array example
+	Pool []interface{}
+	// Generate returns i-th element, e.g. generated from items schema, it returns same element for same index
+	Generate func(i int) interface{}
+}
+
+// Item returns i-th element
+func (it Items) Item(i int) interface{} {
+	if it.Generate != nil {
+		return it.Generate(i)
+	}
+
+	if len(it.Pool) == 0 {
+		return nil
+	}
+
+	return it.Pool[i%len(it.Pool)]
+}
+
+// Slice returns materialized elements
+func (it Items) Slice() []interface{} {
+	res := make([]interface{}, it.Count)
+	for i := range res {
+		res[i] = it.Item(i)
+	}
+
+	return res
+}
+
+// EncodeJSON writes JSON array of elements to w element by element, each of Pool elements is encoded once
+func (it Items) EncodeJSON(w io.Writer) error {
+	if it.Generate != nil {
+		return it.encodeGenerated(w)
+	}
+
+	pool := make([]string, len(it.Pool))
+
+	for i, item := range it.Pool {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		pool[i] = string(b)
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i := 0; i < it.Count; i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		item := "null"
+		if len(pool) > 0 {
+			item = pool[i%len(pool)]
+		}
+
+		if _, err := io.WriteString(w, item); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]")
+
+	return err
+}
+
+// encodeGenerated writes JSON array of elements produced by Generate to w element by element
+func (it Items) encodeGenerated(w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i := 0; i < it.Count; i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		item, err := json.Marshal(it.Generate(i))
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(item); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]")
+
+	return err
+}
+
+// MarshalJSON -.
+func (it Items) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := it.EncodeJSON(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/parse"
+)
+
+func TestItems_EncodeJSON(t *testing.T) {
+	items := api.Items{
+		Count: 5,
+		Pool:  []interface{}{map[string]interface{}{"id": 1}, "<b>", nil},
+	}
+
+	require.Equal(t, []interface{}{map[string]interface{}{"id": 1}, "<b>", nil, map[string]interface{}{"id": 1}, "<b>"}, items.Slice())
+
+	want, err := json.Marshal(items.Slice())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, items.EncodeJSON(&buf))
+	require.Equal(t, string(want), buf.String())
+
+	nested, err := json.Marshal(map[string]interface{}{"items": items})
+	require.NoError(t, err)
+	require.Equal(t, `{"items":`+string(want)+`}`, string(nested))
+
+	empty, err := json.Marshal(api.Items{})
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(empty))
+
+	generated := api.Items{Count: 3, Generate: func(i int) interface{} { return i * 2 }}
+
+	buf.Reset()
+	require.NoError(t, generated.EncodeJSON(&buf))
+	require.Equal(t, "[0,2,4]", buf.String())
+	require.Equal(t, []interface{}{0, 2, 4}, generated.Slice())
+}
+
+func TestBuilder_Build_LargeArray(t *testing.T) {
+	a, err := parse.ParseWithOptions("./testdata/large-array.yml", parse.WithSeed(1))
+	require.NoError(t, err)
+
+	got, ok := a.Operations[0].Responses[0].ExampleValue("").(api.Items)
+	require.True(t, ok)
+	require.Equal(t, 10000, got.Count)
+	require.Empty(t, got.Pool)
+
+	ids := make(map[interface{}]struct{}, got.Count)
+
+	for i := 0; i < got.Count; i++ {
+		item, ok := got.Item(i).(map[string]interface{})
+		require.True(t, ok)
+		require.NotEmpty(t, item["id"])
+
+		ids[item["id"]] = struct{}{}
+	}
+
+	require.Len(t, ids, got.Count)
+
+	// UUIDs of faker are not reproducible by seed, other values of item are
+	name := func(i int) interface{} {
+		return got.Item(i).(map[string]interface{})["name"]
+	}
+
+	require.Equal(t, name(42), name(42))
+}
+
+func BenchmarkItems_EncodeJSON(b *testing.B) {
+	a, err := parse.ParseWithOptions("./testdata/large-array.yml", parse.WithSeed(1))
+	require.NoError(b, err)
+
+	items, ok := a.Operations[0].Responses[0].ExampleValue("").(api.Items)
+	require.True(b, ok)
+
+	b.Run("materialized", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			body, _ := json.Marshal(items.Slice())
+			_, _ = io.Discard.Write(body)
+		}
+	})
+
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = items.EncodeJSON(io.Discard)
+		}
+	})
+}
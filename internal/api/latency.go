@@ -0,0 +1,58 @@
+package api
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Latency is simulated response delay, fixed when Max is not greater than Min,
+// otherwise random in range from Min to Max
+type Latency struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// LatencyError -.
+type LatencyError struct {
+	Value string
+}
+
+// Error -.
+func (e *LatencyError) Error() string {
+	return "invalid latency " + e.Value + ", expected duration, e.g. 500ms, or range, e.g. 100ms-500ms"
+}
+
+// ParseLatency returns latency of fixed duration, e.g. `500ms`, or range, e.g. `100ms-500ms`
+func ParseLatency(s string) (Latency, error) {
+	if s == "" {
+		return Latency{}, nil
+	}
+
+	parts := strings.SplitN(s, "-", 2)
+
+	min, err := time.ParseDuration(strings.TrimSpace(parts[0]))
+	if err != nil || min < 0 {
+		return Latency{}, &LatencyError{Value: s}
+	}
+
+	if len(parts) == 1 {
+		return Latency{Min: min}, nil
+	}
+
+	max, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || max < min {
+		return Latency{}, &LatencyError{Value: s}
+	}
+
+	return Latency{Min: min, Max: max}, nil
+}
+
+// Duration returns delay, random one in range when rnd is set and range is specified
+func (l Latency) Duration(rnd *rand.Rand) time.Duration {
+	if l.Max <= l.Min || nil == rnd {
+		return l.Min
+	}
+
+	return l.Min + time.Duration(rnd.Int63n(int64(l.Max-l.Min)+1))
+}
@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+func TestLatencyError(t *testing.T) {
+	got := &api.LatencyError{
+		Value: "soon",
+	}
+
+	require.Equal(t, got.Error(), "invalid latency soon, expected duration, e.g. 500ms, or range, e.g. 100ms-500ms")
+}
+
+func TestParseLatency(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  api.Latency
+		err   error
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  api.Latency{},
+			err:   nil,
+		},
+		{
+			name:  "fixed",
+			value: "500ms",
+			want:  api.Latency{Min: 500 * time.Millisecond},
+			err:   nil,
+		},
+		{
+			name:  "range",
+			value: "100ms-1s",
+			want:  api.Latency{Min: 100 * time.Millisecond, Max: time.Second},
+			err:   nil,
+		},
+		{
+			name:  "invalid duration",
+			value: "soon",
+			want:  api.Latency{},
+			err:   &api.LatencyError{Value: "soon"},
+		},
+		{
+			name:  "inverted range",
+			value: "1s-100ms",
+			want:  api.Latency{},
+			err:   &api.LatencyError{Value: "1s-100ms"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := api.ParseLatency(tc.value)
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLatency_Duration(t *testing.T) {
+	fixed := api.Latency{Min: 10 * time.Millisecond}
+	require.Equal(t, 10*time.Millisecond, fixed.Duration(api.NewRand(1)))
+
+	r := api.Latency{Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}
+	require.Equal(t, 10*time.Millisecond, r.Duration(nil))
+
+	for i := 0; i < 10; i++ {
+		d := r.Duration(api.NewRand(int64(i)))
+		require.GreaterOrEqual(t, d, r.Min)
+		require.LessOrEqual(t, d, r.Max)
+	}
+}
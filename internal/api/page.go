@@ -0,0 +1,137 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageLimit is count of items of page when request has no `limit` query parameter
+const DefaultPageLimit = 10
+
+// MaxPageLimit is maximum count of items of page, greater `limit` query parameter is clamped to it
+const MaxPageLimit = 100
+
+// maxPageNumber is maximum page number, greater `page` query parameter is clamped to it, so offset
+// of page items does not overflow
+const maxPageNumber = math.MaxInt32
+
+// Properties of pagination envelope, e.g. `{"data": [...], "page": 1, "limit": 10, "total": 42}`
+const (
+	pageDataProperty  = "data"
+	pageProperty      = "page"
+	pageLimitProperty = "limit"
+	pageTotalProperty = "total"
+)
+
+// Page is page of list requested by `page` and `limit` query parameters, numbered from 1
+type Page struct {
+	Number int
+	Limit  int
+}
+
+// ParsePage returns page requested by query parameters, invalid or absent values fall back to first page
+// of DefaultPageLimit items, limit is clamped to MaxPageLimit
+func ParsePage(query url.Values) Page {
+	p := Page{Number: 1, Limit: DefaultPageLimit}
+
+	if n, err := strconv.Atoi(query.Get(pageProperty)); err == nil && n > 0 {
+		p.Number = n
+	}
+
+	if p.Number > maxPageNumber {
+		p.Number = maxPageNumber
+	}
+
+	if n, err := strconv.Atoi(query.Get(pageLimitProperty)); err == nil && n > 0 {
+		p.Limit = n
+	}
+
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+
+	return p
+}
+
+// paginate returns pagination envelope of response with page of items and consistent metadata,
+// false is returned when response schema is not object with array `data` property
+func (r Response) paginate(p Page) (map[string]interface{}, bool) {
+	obj, ok := r.Schema.(ObjectSchema)
+	if !ok {
+		return nil, false
+	}
+
+	data, ok := obj.Properties[pageDataProperty].(ArraySchema)
+	if !ok {
+		return nil, false
+	}
+
+	example, ok := obj.ExampleValue().(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	// total of example is kept unless page is beyond it
+	total, _ := toInt64(example[pageTotalProperty])
+	if min := int64(p.Number * p.Limit); total < min {
+		total = min
+	}
+
+	res := make(map[string]interface{}, len(example))
+	for k, v := range example {
+		res[k] = v
+	}
+
+	items := pageItems(data, (p.Number-1)*p.Limit)
+	if len(items.Pool) > 0 || items.Generate != nil {
+		items.Count = p.Limit
+	}
+
+	res[pageDataProperty] = items.Slice()
+
+	meta := map[string]int64{
+		pageProperty:      int64(p.Number),
+		pageLimitProperty: int64(p.Limit),
+		pageTotalProperty: total,
+	}
+
+	// metadata is set only for properties declared by schema
+	for k, v := range meta {
+		if _, ok := obj.Properties[k]; ok {
+			res[k] = v
+		}
+	}
+
+	return res, true
+}
+
+// pageItems returns items of page starting at offset, items of page cycle through distinct elements
+// of array example or are generated elements of array starting at offset
+func pageItems(a ArraySchema, offset int) Items {
+	if len(a.Example) > 0 {
+		return Items{Pool: a.Example}
+	}
+
+	if g := a.Generated; g != nil {
+		if g.Generate != nil {
+			return Items{Generate: func(i int) interface{} { return g.Generate(offset + i) }}
+		}
+
+		if len(g.Pool) > 0 {
+			return Items{Pool: g.Pool}
+		}
+	}
+
+	if nil == a.Type {
+		return Items{}
+	}
+
+	return Items{Pool: []interface{}{a.Type.ExampleValue()}}
+}
+
+// paginated reports whether response of list request is paginated
+func (a API) paginated(method string) bool {
+	return a.Paginate && (method == http.MethodGet || method == http.MethodHead)
+}
@@ -0,0 +1,138 @@
+package api_test
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/parse"
+)
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+		want  api.Page
+	}{
+		{
+			name:  "default",
+			query: nil,
+			want:  api.Page{Number: 1, Limit: api.DefaultPageLimit},
+		},
+		{
+			name:  "page and limit",
+			query: url.Values{"page": {"2"}, "limit": {"5"}},
+			want:  api.Page{Number: 2, Limit: 5},
+		},
+		{
+			name:  "invalid values",
+			query: url.Values{"page": {"0"}, "limit": {"many"}},
+			want:  api.Page{Number: 1, Limit: api.DefaultPageLimit},
+		},
+		{
+			name:  "limit above maximum",
+			query: url.Values{"page": {"3"}, "limit": {"1000000000"}},
+			want:  api.Page{Number: 3, Limit: api.MaxPageLimit},
+		},
+		{
+			name:  "page above maximum",
+			query: url.Values{"page": {"9223372036854775807"}},
+			want:  api.Page{Number: math.MaxInt32, Limit: api.DefaultPageLimit},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, api.ParsePage(tc.query))
+		})
+	}
+}
+
+func TestAPI_FindResponse_Paginate(t *testing.T) {
+	a, err := parse.Parse("./testdata/pagination.yml")
+	require.NoError(t, err)
+
+	a.Paginate = true
+
+	tests := []struct {
+		name  string
+		query url.Values
+		page  int64
+		limit int64
+		total int64
+	}{
+		{
+			name:  "page and limit",
+			query: url.Values{"page": {"2"}, "limit": {"5"}},
+			page:  2,
+			limit: 5,
+			total: 42,
+		},
+		{
+			name:  "default",
+			query: nil,
+			page:  1,
+			limit: api.DefaultPageLimit,
+			total: 42,
+		},
+		{
+			name:  "page beyond total",
+			query: url.Values{"page": {"10"}, "limit": {"5"}},
+			page:  10,
+			limit: 5,
+			total: 50,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   "/users",
+				Method: http.MethodGet,
+				Query:  tc.query,
+			})
+			require.NoError(t, err)
+
+			envelope, ok := got.ExampleValue("").(map[string]interface{})
+			require.True(t, ok)
+
+			require.Equal(t, tc.page, envelope["page"])
+			require.Equal(t, tc.limit, envelope["limit"])
+			require.Equal(t, tc.total, envelope["total"])
+
+			data, ok := envelope["data"].([]interface{})
+			require.True(t, ok)
+			require.Len(t, data, int(tc.limit))
+			require.Equal(t, map[string]interface{}{"name": "Sergey"}, data[1])
+		})
+	}
+
+	t.Run("not envelope", func(t *testing.T) {
+		got, err := a.FindResponse(api.FindResponseParams{
+			Path:   "/status",
+			Method: http.MethodGet,
+			Query:  url.Values{"page": {"2"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"status": "ok"}, got.ExampleValue(""))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		a.Paginate = false
+
+		got, err := a.FindResponse(api.FindResponseParams{
+			Path:   "/users",
+			Method: http.MethodGet,
+			Query:  url.Values{"page": {"2"}, "limit": {"5"}},
+		})
+		require.NoError(t, err)
+
+		envelope, ok := got.ExampleValue("").(map[string]interface{})
+		require.True(t, ok)
+		require.Len(t, envelope["data"], 2)
+	})
+}
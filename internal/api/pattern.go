@@ -0,0 +1,143 @@
+package api
+
+import (
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// maxRepeat is maximum count of repetitions generated for unbounded quantifiers, e.g. `*` and `+`
+const maxRepeat = 3
+
+// generatePattern returns value matching pattern of string schema and satisfying its length constraints,
+// false is returned for invalid pattern or when no such value is generated in a few attempts
+func (b *Builder) generatePattern(s openapi.Schema) (string, bool) {
+	re, err := syntax.Parse(s.Pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	re = re.Simplify()
+
+	const attempts = 10
+
+	for i := 0; i < attempts; i++ {
+		val := b.generateRegexp(re)
+		if inLength(val, s) {
+			return val, true
+		}
+	}
+
+	return "", false
+}
+
+func (b *Builder) generateRegexp(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		return string(b.pickRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return b.faker().RandomStringElement(words)[:1]
+	case syntax.OpCapture:
+		return b.generateRegexp(re.Sub[0])
+	case syntax.OpStar:
+		return b.repeat(re.Sub[0], 0, maxRepeat)
+	case syntax.OpPlus:
+		return b.repeat(re.Sub[0], 1, maxRepeat)
+	case syntax.OpQuest:
+		return b.repeat(re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		max := re.Max
+		if max < 0 {
+			max = re.Min + maxRepeat
+		}
+
+		return b.repeat(re.Sub[0], re.Min, max)
+	case syntax.OpConcat:
+		var sb strings.Builder
+
+		for _, sub := range re.Sub {
+			sb.WriteString(b.generateRegexp(sub))
+		}
+
+		return sb.String()
+	case syntax.OpAlternate:
+		return b.generateRegexp(re.Sub[b.Rand.Intn(len(re.Sub))])
+	default:
+		// anchors, word boundaries and empty match produce no characters
+		return ""
+	}
+}
+
+// repeat returns from min to max values of re
+func (b *Builder) repeat(re *syntax.Regexp, min, max int) string {
+	var sb strings.Builder
+
+	count := min + b.Rand.Intn(max-min+1)
+
+	for i := 0; i < count; i++ {
+		sb.WriteString(b.generateRegexp(re))
+	}
+
+	return sb.String()
+}
+
+// pickRune returns rune of character class given by pairs of range bounds, printable ASCII is preferred
+func (b *Builder) pickRune(ranges []rune) rune {
+	const (
+		firstPrintable = ' '
+		lastPrintable  = '~'
+	)
+
+	var printable []rune
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < firstPrintable {
+			lo = firstPrintable
+		}
+
+		if hi > lastPrintable {
+			hi = lastPrintable
+		}
+
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+
+	if len(printable) > 0 {
+		ranges = printable
+	}
+
+	if len(ranges) < 2 {
+		return utf8.RuneError
+	}
+
+	i := 2 * b.Rand.Intn(len(ranges)/2)
+
+	return ranges[i] + rune(b.Rand.Intn(int(ranges[i+1]-ranges[i])+1))
+}
+
+// fitLength pads or truncates value to satisfy length constraints of string schema
+func fitLength(value string, s openapi.Schema) string {
+	for i := 0; utf8.RuneCountInString(value) < s.MinLength; i++ {
+		value += words[i%len(words)]
+	}
+
+	if s.MaxLength != nil && *s.MaxLength >= 0 && utf8.RuneCountInString(value) > *s.MaxLength {
+		value = string([]rune(value)[:*s.MaxLength])
+	}
+
+	return value
+}
+
+// inLength reports whether value satisfies length constraints of string schema
+func inLength(value string, s openapi.Schema) bool {
+	n := utf8.RuneCountInString(value)
+
+	return n >= s.MinLength && (nil == s.MaxLength || n <= *s.MaxLength)
+}
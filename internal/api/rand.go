@@ -0,0 +1,39 @@
+package api
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// NewRand returns a new goroutine safe random generator
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{
+		src: rand.NewSource(seed).(rand.Source64),
+	})
+}
+
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.src.Seed(seed)
+}
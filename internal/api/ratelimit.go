@@ -0,0 +1,139 @@
+package api
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// DefaultRateLimitWindow is window of rate limit without one
+const DefaultRateLimitWindow = time.Minute
+
+// RateLimit allows Requests per Window, zero Requests means no limit
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// RateLimitWindowError -.
+type RateLimitWindowError struct {
+	Value string
+}
+
+// Error -.
+func (e *RateLimitWindowError) Error() string {
+	return "invalid rate limit window " + e.Value + ", expected duration, e.g. 1m"
+}
+
+// rateLimit returns rate limit of `x-rate-limit` extension, nil when extension is absent
+func rateLimit(r *openapi.RateLimit) (*RateLimit, error) {
+	if nil == r {
+		return nil, nil
+	}
+
+	res := &RateLimit{Requests: r.Requests}
+
+	if r.Window != "" {
+		window, err := time.ParseDuration(r.Window)
+		if err != nil || window <= 0 {
+			return nil, &RateLimitWindowError{Value: r.Window}
+		}
+
+		res.Window = window
+	}
+
+	return res, nil
+}
+
+// RateLimitError is returned when requests of client exceed rate limit
+type RateLimitError struct {
+	// RetryAfter is duration until window of rate limit is reset
+	RetryAfter time.Duration
+}
+
+// Error -.
+func (e *RateLimitError) Error() string {
+	return "rate limit exceeded, retry after " + e.RetryAfter.String()
+}
+
+// Seconds returns value of `Retry-After` header, i.e. RetryAfter rounded up to whole seconds
+func (e *RateLimitError) Seconds() string {
+	seconds := (e.RetryAfter + time.Second - 1) / time.Second
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return strconv.FormatInt(int64(seconds), 10)
+}
+
+// rateWindow counts requests of client within window started at start
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimiter is concurrency-safe counter of requests by path and client, it simulates rate limiting
+// of fixed windows: once limit is reached, requests are rejected till the end of window
+type RateLimiter struct {
+	// Limit applies to operations without own rate limit
+	Limit RateLimit
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+// NewRateLimiter returns a new instance of RateLimiter with default limit
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		Limit:   limit,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Allow counts request of client to path of operation at time now, RateLimitError is returned when limit is exceeded
+func (l *RateLimiter) Allow(o Operation, path, client string, now time.Time) error {
+	limit := l.Limit
+	if o.RateLimit != nil {
+		limit = *o.RateLimit
+	}
+
+	if limit.Requests <= 0 {
+		return nil
+	}
+
+	if limit.Window <= 0 {
+		limit.Window = DefaultRateLimitWindow
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := path + " " + clientHost(client)
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= limit.Window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= limit.Requests {
+		return &RateLimitError{RetryAfter: w.start.Add(limit.Window).Sub(now)}
+	}
+
+	w.count++
+
+	return nil
+}
+
+// clientHost returns host of client address, e.g. of `host:port` remote address
+func clientHost(client string) string {
+	host, _, err := net.SplitHostPort(client)
+	if err != nil {
+		return client
+	}
+
+	return host
+}
@@ -0,0 +1,89 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+func TestRateLimitError(t *testing.T) {
+	got := &api.RateLimitError{RetryAfter: 1500 * time.Millisecond}
+
+	require.Equal(t, got.Error(), "rate limit exceeded, retry after 1.5s")
+	require.Equal(t, "2", got.Seconds())
+	require.Equal(t, "1", (&api.RateLimitError{}).Seconds())
+}
+
+func TestRateLimitWindowError(t *testing.T) {
+	got := &api.RateLimitWindowError{Value: "soon"}
+
+	require.Equal(t, got.Error(), "invalid rate limit window soon, expected duration, e.g. 1m")
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l := api.NewRateLimiter(api.RateLimit{Requests: 2, Window: time.Minute})
+
+	op := api.Operation{Method: http.MethodGet, Path: "/users"}
+
+	require.NoError(t, l.Allow(op, "/users", "10.0.0.1:1234", now))
+	require.NoError(t, l.Allow(op, "/users", "10.0.0.1:5678", now))
+
+	now = now.Add(20 * time.Second)
+
+	require.Equal(t, &api.RateLimitError{RetryAfter: 40 * time.Second}, l.Allow(op, "/users", "10.0.0.1:1234", now))
+
+	// other clients and paths are counted separately
+	require.NoError(t, l.Allow(op, "/users", "10.0.0.2:1234", now))
+	require.NoError(t, l.Allow(op, "/orgs", "10.0.0.1:1234", now))
+
+	// window is reset once it ends
+	now = now.Add(40 * time.Second)
+
+	require.NoError(t, l.Allow(op, "/users", "10.0.0.1:1234", now))
+
+	t.Run("operation override", func(t *testing.T) {
+		op := api.Operation{Method: http.MethodGet, Path: "/search", RateLimit: &api.RateLimit{Requests: 1, Window: time.Second}}
+
+		require.NoError(t, l.Allow(op, "/search", "10.0.0.1:1234", now))
+		require.Equal(t, &api.RateLimitError{RetryAfter: time.Second}, l.Allow(op, "/search", "10.0.0.1:1234", now))
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		l := api.NewRateLimiter(api.RateLimit{})
+
+		for i := 0; i < 10; i++ {
+			require.NoError(t, l.Allow(op, "/users", "10.0.0.1:1234", now))
+		}
+	})
+}
+
+func TestBuilder_Build_RateLimit(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/search": {
+					Get: &openapi.Operation{
+						Responses: openapi.Responses{"200": &openapi.Response{}},
+						RateLimit: &openapi.RateLimit{Requests: 5, Window: "10s"},
+					},
+				},
+			},
+		},
+	}
+
+	a, err := b.Build()
+	require.NoError(t, err)
+	require.Equal(t, &api.RateLimit{Requests: 5, Window: 10 * time.Second}, a.Operations[0].RateLimit)
+
+	b.OpenAPI.Paths["/search"].Get.RateLimit.Window = "soon"
+
+	_, err = b.Build()
+	require.EqualError(t, err, (&api.RateLimitWindowError{Value: "soon"}).Error())
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"io/fs"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// CircularReferenceError -.
+type CircularReferenceError struct {
+	Ref string
+}
+
+// Error -.
+func (e *CircularReferenceError) Error() string {
+	return "circular reference " + e.Ref
+}
+
+// enter marks reference as being converted, returned func unmarks it
+func (b *Builder) enter(ref string) (func(), error) {
+	if ref == "" {
+		return func() {}, nil
+	}
+
+	ref = b.canonical(ref)
+
+	if b.refs[ref] {
+		return nil, &CircularReferenceError{Ref: ref}
+	}
+
+	if nil == b.refs {
+		b.refs = make(map[string]bool)
+	}
+
+	b.refs[ref] = true
+
+	return func() { delete(b.refs, ref) }, nil
+}
+
+// lookup returns schema by local reference, e.g. `#/components/schemas/User`,
+// by external reference relative to Base, e.g. `./schemas/user.yml#/User` or `common.yml`,
+// or by URL, e.g. `https://example.com/schemas.yml#/User`. External reference starting with `/` is relative to FS root.
+func (b *Builder) lookup(ref string) (openapi.Schema, error) {
+	if strings.HasPrefix(ref, "#") {
+		return b.OpenAPI.LookupByReference(ref)
+	}
+
+	name, fragment := splitReference(b.canonical(ref))
+
+	doc, err := b.document(name)
+	if err != nil {
+		return openapi.Schema{}, err
+	}
+
+	if nil == doc {
+		return openapi.Schema{}, &openapi.SchemaError{Ref: ref}
+	}
+
+	node, ok := pointer(doc, strings.TrimPrefix(fragment, "#"))
+	if !ok {
+		return openapi.Schema{}, &openapi.SchemaError{Ref: ref}
+	}
+
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return openapi.Schema{}, err
+	}
+
+	var schema openapi.Schema
+
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return openapi.Schema{}, err
+	}
+
+	rebase(&schema, name)
+
+	return schema, nil
+}
+
+// document returns external document by canonical name, i.e. URL or path in FS starting with `/`,
+// document is read once. Nil document is returned when document source is not set.
+func (b *Builder) document(name string) (interface{}, error) {
+	if doc, ok := b.documents[name]; ok {
+		return doc, nil
+	}
+
+	file, err := b.read(name)
+	if err != nil || nil == file {
+		return nil, err
+	}
+
+	file, err = openapi.ExpandAliases(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, err
+	}
+
+	if nil == b.documents {
+		b.documents = make(map[string]interface{})
+	}
+
+	b.documents[name] = doc
+
+	return doc, nil
+}
+
+// read returns content of document by canonical name, nil is returned when document source is not set
+func (b *Builder) read(name string) ([]byte, error) {
+	switch {
+	case isURL(name) && b.Fetch != nil:
+		return b.Fetch(name)
+	case !isURL(name) && b.FS != nil:
+		return fs.ReadFile(b.FS, strings.TrimPrefix(name, "/"))
+	default:
+		return nil, nil
+	}
+}
+
+// exampleValue returns value of example, i.e. its inline value or content of its `externalValue` URL or path
+// relative to Base. JSON and YAML content is decoded, content of other formats, e.g. XML, is string value.
+// Nil is returned when source of external value is not set.
+func (b *Builder) exampleValue(e openapi.Example) (interface{}, error) {
+	if e.Value != nil || e.ExternalValue == "" {
+		return e.Value, nil
+	}
+
+	name := b.canonical(e.ExternalValue)
+
+	file, err := b.read(name)
+	if err != nil || nil == file {
+		return nil, err
+	}
+
+	switch path.Ext(name) {
+	case ".json", ".yml", ".yaml":
+	default:
+		if !openapi.IsJSON(file) {
+			return string(file), nil
+		}
+	}
+
+	var value interface{}
+
+	if err := yaml.Unmarshal(file, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// defaultExample returns example of `example` field, otherwise value of default one of named examples,
+// see openapi.Examples.DefaultKey
+func (b *Builder) defaultExample(example interface{}, examples openapi.Examples) (interface{}, error) {
+	if example != nil || len(examples) == 0 {
+		return example, nil
+	}
+
+	return b.exampleValue(examples[examples.DefaultKey()])
+}
+
+// pointer returns node of document by JSON pointer, e.g. `/components/schemas/User`
+func pointer(doc interface{}, p string) (interface{}, bool) {
+	if p == "" || p == "/" {
+		return doc, true
+	}
+
+	node := doc
+
+	for _, token := range strings.Split(strings.TrimPrefix(p, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[token]
+			if !ok {
+				return nil, false
+			}
+
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+// canonical returns external reference relative to FS root, e.g. `/schemas/user.yml#/User`, or URL reference,
+// reference of specification read by URL is resolved against its URL. Local reference is returned as is.
+func (b *Builder) canonical(ref string) string {
+	if strings.HasPrefix(ref, "#") {
+		return ref
+	}
+
+	name, fragment := splitReference(ref)
+
+	base := b.Base
+	if !isURL(base) {
+		base = "/" + base
+	}
+
+	return resolveReference(base, name) + fragment
+}
+
+// resolveReference returns name of document relative to document base, both are URLs or paths starting with `/`
+func resolveReference(base, name string) string {
+	if isURL(name) {
+		return name
+	}
+
+	if isURL(base) {
+		u, err := url.Parse(base)
+		if err != nil {
+			return name
+		}
+
+		ref, err := url.Parse(name)
+		if err != nil {
+			return name
+		}
+
+		return u.ResolveReference(ref).String()
+	}
+
+	if strings.HasPrefix(name, "/") {
+		return path.Clean(name)
+	}
+
+	return path.Join(path.Dir(base), name)
+}
+
+// isURL reports whether name of document is URL, e.g. `https://example.com/schemas.yml`
+func isURL(name string) bool {
+	return strings.Contains(name, "://")
+}
+
+// splitReference returns file path and fragment starting with `#` of reference
+func splitReference(ref string) (string, string) {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		return ref[:i], ref[i:]
+	}
+
+	return ref, ""
+}
+
+// rebase rewrites references of schema from external document with canonical name
+// to canonical references, so they can be resolved outside of the document
+func rebase(s *openapi.Schema, name string) {
+	if nil == s {
+		return
+	}
+
+	switch {
+	case s.Ref == "":
+	case strings.HasPrefix(s.Ref, "#"):
+		s.Ref = name + s.Ref
+	default:
+		ref, fragment := splitReference(s.Ref)
+		s.Ref = resolveReference(name, ref) + fragment
+	}
+
+	for _, p := range s.Properties {
+		rebase(p, name)
+	}
+
+	for _, members := range [][]*openapi.Schema{s.AllOf, s.OneOf, s.AnyOf, s.PrefixItems} {
+		for _, m := range members {
+			rebase(m, name)
+		}
+	}
+
+	rebase(s.Items, name)
+
+	if s.AdditionalProperties != nil {
+		rebase(s.AdditionalProperties.Schema, name)
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import "strings"
+
+// router is tree of path segments indexing operations
+type router struct {
+	static map[string]*router
+	param  *router
+	// catchAll are operations with path ending with catch-all parameter at the node
+	catchAll []Operation
+	// operations are operations with path ending at the node
+	operations []Operation
+}
+
+// newRouter returns router of operations, nil is returned when there are no operations
+func newRouter(operations []Operation) *router {
+	if len(operations) == 0 {
+		return nil
+	}
+
+	r := &router{}
+
+	for _, op := range operations {
+		r.insert(strings.Split(op.Path, "/"), op)
+	}
+
+	return r
+}
+
+func (r *router) insert(segments []string, op Operation) {
+	if len(segments) == 0 {
+		r.operations = append(r.operations, op)
+
+		return
+	}
+
+	segment := segments[0]
+
+	// trailing optional parameter may be omitted
+	if len(segments) == 1 && isOptionalParam(segment) {
+		r.operations = append(r.operations, op)
+	}
+
+	if len(segments) == 1 && isCatchAllParam(segment) {
+		r.catchAll = append(r.catchAll, op)
+
+		return
+	}
+
+	if isParam(segment) {
+		if nil == r.param {
+			r.param = &router{}
+		}
+
+		r.param.insert(segments[1:], op)
+
+		return
+	}
+
+	if nil == r.static {
+		r.static = make(map[string]*router)
+	}
+
+	child, ok := r.static[segment]
+	if !ok {
+		child = &router{}
+		r.static[segment] = child
+	}
+
+	child.insert(segments[1:], op)
+}
+
+// walk calls fn with operations of each node matching path segments
+func (r *router) walk(segments []string, fn func(operations []Operation)) {
+	if len(segments) == 0 {
+		if len(r.operations) > 0 {
+			fn(r.operations)
+		}
+
+		return
+	}
+
+	if len(r.catchAll) > 0 && strings.Join(segments, "") != "" {
+		fn(r.catchAll)
+	}
+
+	if child, ok := r.static[segments[0]]; ok {
+		child.walk(segments[1:], fn)
+	}
+
+	if r.param != nil {
+		r.param.walk(segments[1:], fn)
+	}
+}
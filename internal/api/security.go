@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// ErrUnauthorized is returned when operation requires credentials, but request has none
+var ErrUnauthorized = errors.New("unauthorized")
+
+// SecuritySchemeError -.
+type SecuritySchemeError struct {
+	Name string
+}
+
+// Error -.
+func (e *SecuritySchemeError) Error() string {
+	return "unknown security scheme " + e.Name
+}
+
+// SecurityScheme is security scheme checked by presence of credentials, their values are not validated
+type SecurityScheme struct {
+	Type string
+	// Name and In are name and location of API key: header, query or cookie
+	Name string
+	In   string
+	// Scheme is HTTP authorization scheme, e.g. bearer or basic
+	Scheme string
+}
+
+// SecurityRequirement contains security schemes, all of them are to be satisfied
+type SecurityRequirement []SecurityScheme
+
+// security returns security requirements of operation, operation requirements override default ones
+func (b *Builder) security(o *openapi.Operation) ([]SecurityRequirement, error) {
+	requirements := b.OpenAPI.Security
+	if o.Security != nil {
+		requirements = *o.Security
+	}
+
+	if len(requirements) == 0 {
+		return nil, nil
+	}
+
+	res := make([]SecurityRequirement, 0, len(requirements))
+
+	for _, r := range requirements {
+		names := make([]string, 0, len(r))
+		for name := range r {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		requirement := make(SecurityRequirement, 0, len(names))
+
+		for _, name := range names {
+			scheme, ok := b.OpenAPI.Components.SecuritySchemes[name]
+			if !ok || nil == scheme {
+				return nil, &SecuritySchemeError{Name: name}
+			}
+
+			requirement = append(requirement, SecurityScheme{
+				Type:   scheme.Type,
+				Name:   scheme.Name,
+				In:     scheme.In,
+				Scheme: scheme.Scheme,
+			})
+		}
+
+		res = append(res, requirement)
+	}
+
+	return res, nil
+}
+
+// authorized reports whether request satisfies any of operation security requirements
+func (o Operation) authorized(params FindResponseParams) bool {
+	if len(o.Security) == 0 {
+		return true
+	}
+
+	for _, requirement := range o.Security {
+		if requirement.satisfied(params) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r SecurityRequirement) satisfied(params FindResponseParams) bool {
+	for _, scheme := range r {
+		if !scheme.present(params) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// present reports whether request has credentials of security scheme, unsupported schemes are always satisfied
+func (s SecurityScheme) present(params FindResponseParams) bool {
+	authorization := params.Header.Get("Authorization")
+
+	switch s.Type {
+	case "apiKey":
+		switch s.In {
+		case "query":
+			return params.Query.Get(s.Name) != ""
+		case "cookie":
+			_, err := (&http.Request{Header: params.Header}).Cookie(s.Name)
+
+			return err == nil
+		default:
+			return params.Header.Get(s.Name) != ""
+		}
+	case "http":
+		if s.Scheme == "" {
+			return authorization != ""
+		}
+
+		return hasAuthScheme(authorization, s.Scheme)
+	case "oauth2", "openIdConnect":
+		return hasAuthScheme(authorization, "bearer")
+	default:
+		return true
+	}
+}
+
+// hasAuthScheme reports whether Authorization header value has credentials of case-insensitive scheme
+func hasAuthScheme(authorization, scheme string) bool {
+	prefix := scheme + " "
+
+	return len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix)
+}
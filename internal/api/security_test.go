@@ -0,0 +1,121 @@
+package api_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/openapi"
+	"github.com/neotoolkit/dummy/internal/parse"
+)
+
+func TestSecuritySchemeError(t *testing.T) {
+	got := &api.SecuritySchemeError{
+		Name: "bearer",
+	}
+
+	require.Equal(t, got.Error(), "unknown security scheme bearer")
+}
+
+func TestBuilder_Build_UnknownSecurityScheme(t *testing.T) {
+	b := api.Builder{
+		OpenAPI: openapi.OpenAPI{
+			Paths: openapi.Paths{
+				"/profile": {Get: &openapi.Operation{}},
+			},
+			Security: openapi.SecurityRequirements{
+				{"bearer": {}},
+			},
+		},
+	}
+
+	_, err := b.Build()
+	require.EqualError(t, err, "unknown security scheme bearer")
+}
+
+func TestAPI_FindResponse_Security(t *testing.T) {
+	a, err := parse.Parse("./testdata/security.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		path   string
+		query  url.Values
+		header http.Header
+		err    error
+	}{
+		{
+			name:   "default bearer",
+			path:   "/profile",
+			header: http.Header{"Authorization": {"Bearer token"}},
+			err:    nil,
+		},
+		{
+			name:   "default bearer is absent",
+			path:   "/profile",
+			header: nil,
+			err:    api.ErrUnauthorized,
+		},
+		{
+			name:   "other authorization scheme",
+			path:   "/profile",
+			header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			err:    api.ErrUnauthorized,
+		},
+		{
+			name:   "security disabled",
+			path:   "/health",
+			header: nil,
+			err:    nil,
+		},
+		{
+			name:   "api key",
+			path:   "/keys",
+			header: http.Header{"X-Api-Key": {"secret"}},
+			err:    nil,
+		},
+		{
+			name:   "api key is absent",
+			path:   "/keys",
+			header: http.Header{"Authorization": {"Bearer token"}},
+			err:    api.ErrUnauthorized,
+		},
+		{
+			name:  "query api key",
+			path:  "/reports",
+			query: url.Values{"api_key": {"secret"}},
+			err:   nil,
+		},
+		{
+			name: "basic and cookie",
+			path: "/reports",
+			header: http.Header{
+				"Authorization": {"basic dXNlcjpwYXNz"},
+				"Cookie":        {"session=abc"},
+			},
+			err: nil,
+		},
+		{
+			name:   "basic without cookie",
+			path:   "/reports",
+			header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			err:    api.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindResponse(api.FindResponseParams{
+				Path:   tc.path,
+				Method: http.MethodGet,
+				Query:  tc.query,
+				Header: tc.header,
+			})
+
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
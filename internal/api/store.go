@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/neotoolkit/faker"
+)
+
+// ErrResourceNotFound -.
+var ErrResourceNotFound = errors.New("resource not found")
+
+// Store is concurrency-safe in-memory storage of resources keyed by collection path, e.g. `/users`
+type Store struct {
+	mu          sync.Mutex
+	faker       faker.Faker
+	collections map[string][]map[string]interface{}
+}
+
+// NewStore returns a new instance of Store, rnd is used for id generation
+func NewStore(rnd *rand.Rand) *Store {
+	return &Store{
+		faker:       faker.Faker{Generator: rnd},
+		collections: make(map[string][]map[string]interface{}),
+	}
+}
+
+// Create saves item to collection, id is generated if item has no one
+func (s *Store) Create(collection string, item map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make(map[string]interface{}, len(item)+1)
+	for k, v := range item {
+		res[k] = v
+	}
+
+	if _, ok := res["id"]; !ok {
+		res["id"] = s.faker.UUID().V4()
+	}
+
+	s.collections[collection] = append(s.collections[collection], res)
+
+	return res
+}
+
+// List returns items of collection, false is returned if nothing was created in collection
+func (s *Store) List(collection string) ([]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, ok := s.collections[collection]
+	if !ok {
+		return nil, false
+	}
+
+	res := make([]interface{}, len(items))
+	for i, item := range items {
+		res[i] = item
+	}
+
+	return res, true
+}
+
+// Get returns item of collection by id
+func (s *Store) Get(collection, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range s.collections[collection] {
+		if fmt.Sprint(item["id"]) == id {
+			return item, true
+		}
+	}
+
+	return nil, false
+}
+
+// Delete removes item of collection by id, false is returned if there is no such item
+func (s *Store) Delete(collection, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.collections[collection]
+
+	for i, item := range items {
+		if fmt.Sprint(item["id"]) == id {
+			s.collections[collection] = append(items[:i:i], items[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// stateful returns response body of stateful mode: POST creates item of collection, GET returns
+// collection or item, DELETE removes item. False is returned when static example should be used.
+func (s *Store) stateful(method, path string, o Operation, body map[string]interface{}) (interface{}, bool, error) {
+	segments := strings.Split(o.Path, "/")
+	item := len(segments) > 1 && strings.HasPrefix(segments[len(segments)-1], "{")
+
+	collection, id := path, ""
+	if item {
+		i := strings.LastIndex(path, "/")
+		collection, id = path[:i], path[i+1:]
+	}
+
+	switch {
+	case method == http.MethodPost && !item:
+		return s.Create(collection, body), true, nil
+	case method == http.MethodGet && !item:
+		items, ok := s.List(collection)
+		return items, ok, nil
+	case method == http.MethodGet && item:
+		res, ok := s.Get(collection, id)
+		if !ok {
+			return nil, false, ErrResourceNotFound
+		}
+
+		return res, true, nil
+	case method == http.MethodDelete && item:
+		if !s.Delete(collection, id) {
+			return nil, false, ErrResourceNotFound
+		}
+
+		return nil, false, nil
+	default:
+		return nil, false, nil
+	}
+}
@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+func TestStore(t *testing.T) {
+	s := api.NewStore(api.NewRand(1))
+
+	_, ok := s.List("/users")
+	require.False(t, ok)
+
+	item := s.Create("/users", map[string]interface{}{"firstName": "Elon"})
+	require.NotEmpty(t, item["id"])
+
+	id := fmt.Sprint(item["id"])
+
+	got, ok := s.Get("/users", id)
+	require.True(t, ok)
+	require.Equal(t, item, got)
+
+	items, ok := s.List("/users")
+	require.True(t, ok)
+	require.Equal(t, []interface{}{item}, items)
+
+	require.True(t, s.Delete("/users", id))
+	require.False(t, s.Delete("/users", id))
+
+	_, ok = s.Get("/users", id)
+	require.False(t, ok)
+
+	items, ok = s.List("/users")
+	require.True(t, ok)
+	require.Empty(t, items)
+}
+
+func TestStore_Concurrent(t *testing.T) {
+	s := api.NewStore(api.NewRand(1))
+
+	const n = 100
+
+	var wg sync.WaitGroup
+
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			item := s.Create("/users", map[string]interface{}{"id": i})
+			s.Get("/users", fmt.Sprint(item["id"]))
+			s.List("/users")
+		}(i)
+	}
+
+	wg.Wait()
+
+	items, ok := s.List("/users")
+	require.True(t, ok)
+	require.Len(t, items, n)
+}
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// ExampleMismatch is example of response, request body or parameter not matching its schema
+type ExampleMismatch struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// In is location of request example, e.g. `requestBody` or `query parameter id`, empty for response example
+	In string
+	// Example is name of example, empty for `example` of media type
+	Example string
+	Message string
+}
+
+// String -.
+func (m ExampleMismatch) String() string {
+	example := "example"
+	if m.Example != "" {
+		example += " " + m.Example
+	}
+
+	location := strconv.Itoa(m.StatusCode)
+	if m.In != "" {
+		location = m.In
+	}
+
+	return m.Method + " " + m.Path + " " + location + " " + example + ": " + m.Message
+}
+
+// ExampleError is returned by Build in strict mode for examples not matching their schemas
+type ExampleError struct {
+	Mismatches []ExampleMismatch
+}
+
+// Error -.
+func (e *ExampleError) Error() string {
+	mismatches := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		mismatches[i] = m.String()
+	}
+
+	return "examples do not match schemas: " + strings.Join(mismatches, "; ")
+}
+
+// checkRequestExamples records mismatches of examples of parameters and of JSON or XML request body
+// with their schemas
+func (b *Builder) checkRequestExamples(method, path string, o *openapi.Operation) error {
+	for _, p := range o.Parameters {
+		if nil == p.Schema {
+			continue
+		}
+
+		at := ExampleMismatch{Method: method, Path: path, In: p.In + " parameter " + p.Name}
+		if err := b.checkExamples(at, p.Example, p.Examples, *p.Schema); err != nil {
+			return err
+		}
+	}
+
+	for _, mediaType := range sortedMediaTypes(o.RequestBody.Content) {
+		if mediaType != MediaTypeJSON && !IsXML(mediaType) {
+			continue
+		}
+
+		content := o.RequestBody.Content[mediaType]
+
+		at := ExampleMismatch{Method: method, Path: path, In: "requestBody"}
+		if err := b.checkExamples(at, content.Example, content.Examples, content.Schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkExamples records mismatches of example and named examples with schema, at is location of examples
+func (b *Builder) checkExamples(at ExampleMismatch, example interface{}, named openapi.Examples, schema openapi.Schema) error {
+	if example != nil {
+		if err := b.checkExample(at, "", example, schema); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range named.GetKeys() {
+		value, err := b.exampleValue(named[name])
+		if err != nil {
+			return err
+		}
+
+		if err := b.checkExample(at, name, value, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkExample records mismatches of example of name with schema, at is location of example
+func (b *Builder) checkExample(at ExampleMismatch, name string, example interface{}, schema openapi.Schema) error {
+	messages, err := b.checkValue(schema, example, "")
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		mismatch := at
+		mismatch.Example, mismatch.Message = name, message
+
+		b.mismatches = append(b.mismatches, mismatch)
+	}
+
+	return nil
+}
+
+// checkValue returns messages describing mismatches of value with schema, field is path of value in example.
+// Value matches any branch of oneOf and anyOf, schema without type matches any value.
+func (b *Builder) checkValue(s openapi.Schema, value interface{}, field string) ([]string, error) {
+	s, err := b.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := s.OneOf
+	if len(branches) == 0 {
+		branches = s.AnyOf
+	}
+
+	if len(branches) > 0 {
+		return b.checkBranches(s, branches, value, field)
+	}
+
+	if nil == value {
+		if s.Nullable || s.Type == "" {
+			return nil, nil
+		}
+
+		return []string{fieldName(field) + " must not be null"}, nil
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fieldName(field) + " must be object"}, nil
+		}
+
+		return b.checkObject(s, obj, field)
+	case "array":
+		items, ok := arrayItems(value)
+		if !ok {
+			return []string{fieldName(field) + " must be array"}, nil
+		}
+
+		if nil == s.Items {
+			return nil, nil
+		}
+
+		var messages []string
+
+		for i, item := range items {
+			m, err := b.checkValue(*s.Items, item, field+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, err
+			}
+
+			messages = append(messages, m...)
+		}
+
+		return messages, nil
+	case "string", "integer", "number", "boolean":
+		if !matchType(s.Type, value) {
+			return []string{fieldName(field) + " must be " + s.Type}, nil
+		}
+
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+// checkBranches returns no messages for value matching any branch, otherwise messages of first branch
+func (b *Builder) checkBranches(s openapi.Schema, branches []*openapi.Schema, value interface{}, field string) ([]string, error) {
+	s.OneOf, s.AnyOf = nil, nil
+
+	var first []string
+
+	for i, branch := range branches {
+		if nil == branch {
+			continue
+		}
+
+		chosen, err := b.resolve(*branch)
+		if err != nil {
+			return nil, err
+		}
+
+		messages, err := b.checkValue(mergeSchemas(s, chosen), value, field)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(messages) == 0 {
+			return nil, nil
+		}
+
+		if i == 0 {
+			first = messages
+		}
+	}
+
+	return first, nil
+}
+
+// checkObject returns messages of missing required properties, not allowed properties and mismatched values
+func (b *Builder) checkObject(s openapi.Schema, obj map[string]interface{}, field string) ([]string, error) {
+	var messages []string
+
+	for _, name := range s.Required {
+		// write-only property is not sent in responses
+		if prop := s.Properties[name]; prop != nil && prop.WriteOnly {
+			continue
+		}
+
+		if _, ok := obj[name]; !ok {
+			messages = append(messages, fieldName(join(field, name))+" is required")
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		prop, ok := s.Properties[key]
+		if !ok || nil == prop {
+			if s.AdditionalProperties != nil && !s.AdditionalProperties.Allowed {
+				messages = append(messages, fieldName(join(field, key))+" is not allowed")
+			}
+
+			continue
+		}
+
+		m, err := b.checkValue(*prop, obj[key], join(field, key))
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, m...)
+	}
+
+	return messages, nil
+}
+
+// matchType reports whether example value decoded by YAML or JSON decoder is of primitive type
+func matchType(typ string, value interface{}) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		if v, ok := value.(float64); ok {
+			return v == math.Trunc(v)
+		}
+
+		if _, ok := value.(uint64); ok {
+			return true
+		}
+
+		_, ok := toInt64(value)
+
+		return ok
+	default:
+		_, ok := toInt64(value)
+
+		return ok
+	}
+}
+
+// arrayItems returns elements of array example
+func arrayItems(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+
+	return field + "." + name
+}
+
+func fieldName(field string) string {
+	if field == "" {
+		return "example"
+	}
+
+	return "field " + field
+}
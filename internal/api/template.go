@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+//nolint:gochecknoglobals // placeholder matches `{{source.name}}` placeholders of request values and `{param}` ones
+var placeholder = regexp.MustCompile(`\{\{\s*((?:body|query|header|cookie)\.[^{}\s]+)\s*\}\}|\{([A-Za-z0-9_.-]+)\}`)
+
+// RequestValues are values of matched request substituted into placeholders of response examples:
+// `{param}` by path parameter, `{{body.field}}` by request body field, e.g. `{{body.user.name}}`,
+// `{{query.name}}` by query parameter, `{{header.Name}}` by case-insensitive header
+// and `{{cookie.name}}` by cookie. Placeholders without values are left as is.
+type RequestValues struct {
+	Params map[string]string
+	// TypedParams are path parameters converted to types of their schemas, e.g. integer
+	TypedParams map[string]interface{}
+	// Query contains first values of query parameters converted to types of declared ones
+	Query map[string]interface{}
+	// Header contains first values of headers by canonical names, e.g. `X-Request-Id`
+	Header map[string]interface{}
+	Cookie map[string]interface{}
+	Body   map[string]interface{}
+}
+
+// render returns copy of value with placeholders substituted
+func (v *RequestValues) render(value interface{}) interface{} {
+	if nil == v {
+		return value
+	}
+
+	switch val := value.(type) {
+	case string:
+		return v.renderString(val)
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res[k] = v.render(item)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, item := range val {
+			res[i] = v.render(item)
+		}
+
+		return res
+	case []map[string]interface{}:
+		res := make([]map[string]interface{}, len(val))
+		for i, item := range val {
+			res[i], _ = v.render(item).(map[string]interface{})
+		}
+
+		return res
+	default:
+		return value
+	}
+}
+
+// renderString substitutes placeholders of s. String consisting of single body, query or typed parameter
+// placeholder is replaced by value as is, so numbers and objects keep their types.
+func (v *RequestValues) renderString(s string) interface{} {
+	if m := placeholder.FindStringSubmatch(s); m != nil && m[0] == s {
+		if val, ok := v.value(m[1]); ok && m[1] != "" {
+			return val
+		}
+
+		if val, ok := v.TypedParams[m[2]]; ok && m[2] != "" {
+			return val
+		}
+	}
+
+	return placeholder.ReplaceAllStringFunc(s, func(token string) string {
+		m := placeholder.FindStringSubmatch(token)
+
+		if m[1] != "" {
+			if val, ok := v.value(m[1]); ok {
+				return fmt.Sprint(val)
+			}
+
+			return token
+		}
+
+		if val, ok := v.Params[m[2]]; ok {
+			return val
+		}
+
+		return token
+	})
+}
+
+// value returns value of request by dot-separated path, e.g. `body.user.name` or `query.page`
+func (v *RequestValues) value(path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+
+	var value interface{}
+
+	switch keys[0] {
+	case "body":
+		value = v.Body
+	case "query":
+		value = v.Query
+	case "header":
+		if len(keys) == 2 {
+			keys[1] = http.CanonicalHeaderKey(keys[1])
+		}
+
+		value = v.Header
+	case "cookie":
+		value = v.Cookie
+	default:
+		return nil, false
+	}
+
+	for _, key := range keys[1:] {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		value, ok = obj[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
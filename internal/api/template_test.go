@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/parse"
+)
+
+func TestAPI_FindResponse_Template(t *testing.T) {
+	a, err := parse.Parse("./testdata/template.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   interface{}
+	}{
+		{
+			name:   "body values",
+			method: http.MethodPost,
+			path:   "/users",
+			body:   `{"name": "Ada", "age": 36}`,
+			want: map[string]interface{}{
+				"id":       uint64(42),
+				"name":     "Ada",
+				"age":      float64(36),
+				"greeting": "Hello, Ada!",
+				"city":     "{{body.address.city}}",
+			},
+		},
+		{
+			name:   "path parameters",
+			method: http.MethodGet,
+			path:   "/orgs/neotoolkit/users/7",
+			want: map[string]interface{}{
+				"id":   "7",
+				"url":  "/orgs/neotoolkit/users/7",
+				"tags": []interface{}{"{unknown}", "neotoolkit"},
+			},
+		},
+		{
+			name:   "typed path parameters",
+			method: http.MethodGet,
+			path:   "/orders/7",
+			want: map[string]interface{}{
+				"id":  float64(7),
+				"url": "/orders/7",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.FindResponse(api.FindResponseParams{
+				Path:   tc.path,
+				Method: tc.method,
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.ExampleValue(""))
+		})
+	}
+
+	// examples are not modified by rendering
+	got, err := a.FindResponse(api.FindResponseParams{
+		Path:   "/users",
+		Method: http.MethodPost,
+		Body:   io.NopCloser(strings.NewReader(`{"name": "Grace"}`)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Grace", got.ExampleValue("").(map[string]interface{})["name"])
+}
@@ -0,0 +1,386 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ValidationMode defines handling of invalid request body and parameters
+type ValidationMode int
+
+const (
+	// ValidationStrict rejects invalid request
+	ValidationStrict ValidationMode = iota
+	// ValidationWarn logs validation error of request and responds as to valid one
+	ValidationWarn
+	// ValidationOff skips request validation
+	ValidationOff
+)
+
+// ValidationModeError -.
+type ValidationModeError struct {
+	Mode string
+}
+
+// Error -.
+func (e *ValidationModeError) Error() string {
+	return "unknown validation mode: " + e.Mode
+}
+
+// ParseValidationMode returns ValidationMode by name
+func ParseValidationMode(mode string) (ValidationMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "strict":
+		return ValidationStrict, nil
+	case "warn":
+		return ValidationWarn, nil
+	case "off":
+		return ValidationOff, nil
+	default:
+		return ValidationStrict, &ValidationModeError{Mode: mode}
+	}
+}
+
+// Codes of request validation errors
+const (
+	CodeMalformedBody          = "malformed_body"
+	CodeDuplicateKeys          = "duplicate_keys"
+	CodeRequiredFieldMissing   = "required_field_missing"
+	CodeInvalidType            = "invalid_type"
+	CodeNotInEnum              = "not_in_enum"
+	CodeInvalidLength          = "invalid_length"
+	CodePatternMismatch        = "pattern_mismatch"
+	CodeInvalidPropertiesCount = "invalid_properties_count"
+	CodeUnknownField           = "unknown_field"
+	CodeOutOfRange             = "out_of_range"
+	CodeInvalidFormat          = "invalid_format"
+	CodeInvalidContentType     = "invalid_content_type"
+)
+
+// ValidationError is request validation error with name of invalid field, if any, and machine-readable code.
+// It wraps cause, e.g. ErrEmptyRequireField or *FieldTypeError.
+type ValidationError struct {
+	// Field is name of invalid field, nested fields are named by path, e.g. `address.city` or `tags[0]`
+	Field string
+	Code  string
+	Err   error
+	// In is location of invalid parameter, e.g. query or header, it is empty for request body
+	In string
+	// Violations contain all violations of request body, starting with this one, when there are several
+	Violations []*ValidationError
+}
+
+// Error -.
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap -.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Malformed reports whether request body is not decodable, e.g. invalid JSON
+func (e *ValidationError) Malformed() bool {
+	return e.Code == CodeMalformedBody || e.Code == CodeDuplicateKeys
+}
+
+// violationsError returns first of violations with all of them, nil is returned when there are no violations
+func violationsError(violations []*ValidationError) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	err := *violations[0]
+	if len(violations) > 1 {
+		err.Violations = violations
+	}
+
+	return &err
+}
+
+// appendViolations appends violations of validation error to list
+func appendViolations(violations []*ValidationError, err error) []*ValidationError {
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		return violations
+	}
+
+	if len(validationError.Violations) > 0 {
+		return append(violations, validationError.Violations...)
+	}
+
+	return append(violations, validationError)
+}
+
+// decodeError returns validation error of request body decoding
+func decodeError(err error) error {
+	var duplicateKeyError *DuplicateKeyError
+	if errors.As(err, &duplicateKeyError) {
+		return &ValidationError{Code: CodeDuplicateKeys, Err: err}
+	}
+
+	var contentTypeError *PartContentTypeError
+	if errors.As(err, &contentTypeError) {
+		return &ValidationError{Field: contentTypeError.Field, Code: CodeInvalidContentType, Err: err}
+	}
+
+	return &ValidationError{Code: CodeMalformedBody, Err: err}
+}
+
+// validateFields returns validation error of decoded request body with all its violations, in order of fields
+func (o Operation) validateFields(body map[string]interface{}) error {
+	violations := fieldsViolations("", o.Body, body)
+
+	violations = appendViolations(violations, o.validateAdditionalFields(body))
+
+	if err := o.validatePropertiesCount(len(body)); err != nil {
+		violations = append(violations, &ValidationError{Code: CodeInvalidPropertiesCount, Err: err})
+	}
+
+	violations = appendViolations(violations, o.validateVariant(body))
+
+	return violationsError(violations)
+}
+
+// fieldsViolations returns violations of properties of object, names of nested fields are prefixed by parent path
+func fieldsViolations(parent string, fields map[string]FieldType, obj map[string]interface{}) []*ValidationError {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var violations []*ValidationError
+
+	for _, name := range names {
+		field, path := fields[name], name
+		if parent != "" {
+			path = parent + "." + name
+		}
+
+		value, ok := obj[name]
+		if !ok {
+			if field.Required {
+				violations = append(violations,
+					&ValidationError{Field: path, Code: CodeRequiredFieldMissing, Err: ErrEmptyRequireField})
+			}
+
+			continue
+		}
+
+		violations = append(violations, field.violations(path, value)...)
+	}
+
+	return violations
+}
+
+// validateParams returns validation error of first found invalid query, header or cookie parameter
+func (o Operation) validateParams(params FindResponseParams) error {
+	if err := validateParams(o.QueryParams, func(name string) (string, bool) {
+		values, ok := params.Query[name]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	}); err != nil {
+		return paramError("query", err)
+	}
+
+	if err := validateParams(o.HeaderParams, func(name string) (string, bool) {
+		values := params.Header.Values(name)
+		if len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	}); err != nil {
+		return paramError("header", err)
+	}
+
+	r := &http.Request{Header: params.Header}
+
+	return paramError("cookie", validateParams(o.CookieParams, func(name string) (string, bool) {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return "", false
+		}
+
+		return c.Value, true
+	}))
+}
+
+// validateParams returns validation error of first found invalid parameter in order of names,
+// lookup returns value of parameter and whether it is present
+func validateParams(fields map[string]FieldType, lookup func(name string) (string, bool)) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fields[name]
+
+		value, ok := lookup(name)
+		if !ok {
+			if field.Required {
+				return &ValidationError{Field: name, Code: CodeRequiredFieldMissing, Err: ErrEmptyRequireField}
+			}
+
+			continue
+		}
+
+		if err := field.validate(name, paramValue(value, field)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paramError returns validation error of parameter located in request part, e.g. query
+func paramError(in string, err error) error {
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		validationError.In = in
+	}
+
+	return err
+}
+
+// validateVariant returns validation error of request body against variant selected by discriminator field
+func (o Operation) validateVariant(body map[string]interface{}) error {
+	d := o.BodyDiscriminator
+	if nil == d {
+		return nil
+	}
+
+	value, ok := body[d.Field]
+	if !ok {
+		return &ValidationError{Field: d.Field, Code: CodeRequiredFieldMissing, Err: ErrEmptyRequireField}
+	}
+
+	s, _ := value.(string)
+
+	variant, ok := d.Variants[s]
+	if !ok {
+		return &ValidationError{Field: d.Field, Code: CodeNotInEnum, Err: &EnumError{Field: d.Field, Value: value}}
+	}
+
+	return variant.validateFields(body)
+}
+
+// validateAdditionalFields returns validation error of first found request body field not listed in operation body
+func (o Operation) validateAdditionalFields(body map[string]interface{}) error {
+	if !o.BodyStrict && nil == o.BodyAdditionalProperties {
+		return nil
+	}
+
+	keys := make([]string, 0, len(body))
+	for k := range body {
+		if _, ok := o.Body[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if o.BodyStrict {
+			return &ValidationError{Field: k, Code: CodeUnknownField, Err: &UnknownFieldError{Field: k}}
+		}
+
+		if err := o.BodyAdditionalProperties.validate(k, body[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate returns validation error of field value, i.e. its first violation
+func (f FieldType) validate(field string, value interface{}) error {
+	if violations := f.violations(field, value); len(violations) > 0 {
+		return violations[0]
+	}
+
+	return nil
+}
+
+// violations returns violations of field value including ones of its properties and items
+func (f FieldType) violations(field string, value interface{}) []*ValidationError {
+	// explicit null is valid value of nullable field
+	if nil == value && f.Nullable {
+		return nil
+	}
+
+	if !f.match(value) {
+		return []*ValidationError{
+			{Field: field, Code: CodeInvalidType, Err: &FieldTypeError{Field: field, Type: f.Type}},
+		}
+	}
+
+	if !f.allowed(value) {
+		return []*ValidationError{
+			{Field: field, Code: CodeNotInEnum, Err: &EnumError{Field: field, Value: value}},
+		}
+	}
+
+	if err := f.validateNumber(field, value); err != nil {
+		return []*ValidationError{err}
+	}
+
+	if err := f.validateString(field, value); err != nil {
+		return []*ValidationError{err}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return fieldsViolations(field, f.Properties, v)
+	case []interface{}:
+		if nil == f.Items {
+			return nil
+		}
+
+		var violations []*ValidationError
+
+		for i, item := range v {
+			violations = append(violations, f.Items.violations(fmt.Sprintf("%s[%d]", field, i), item)...)
+		}
+
+		return violations
+	default:
+		return nil
+	}
+}
+
+// validateNumber returns error if numeric value violates bounds of field, values of other types are not checked
+func (f FieldType) validateNumber(field string, value interface{}) *ValidationError {
+	n, ok := value.(float64)
+	if !ok {
+		return nil
+	}
+
+	bound, limit := f.Bounds.violated(n)
+	if bound == "" {
+		return nil
+	}
+
+	return &ValidationError{
+		Field: field,
+		Code:  CodeOutOfRange,
+		Err: &RangeError{
+			Field: field,
+			Value: n,
+			Bound: bound,
+			Limit: limit,
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package api_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+func TestValidationError(t *testing.T) {
+	got := &api.ValidationError{
+		Field: "id",
+		Code:  api.CodeInvalidType,
+		Err:   &api.FieldTypeError{Field: "id", Type: "string"},
+	}
+
+	require.Equal(t, got.Error(), "field id must be string")
+
+	var fieldTypeError *api.FieldTypeError
+	require.True(t, errors.As(got, &fieldTypeError))
+	require.True(t, errors.Is(&api.ValidationError{Err: api.ErrEmptyRequireField}, api.ErrEmptyRequireField))
+}
+
+func TestParseValidationMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		want api.ValidationMode
+		err  error
+	}{
+		{
+			name: "default",
+			mode: "",
+			want: api.ValidationStrict,
+			err:  nil,
+		},
+		{
+			name: "warn",
+			mode: "WARN",
+			want: api.ValidationWarn,
+			err:  nil,
+		},
+		{
+			name: "off",
+			mode: "off",
+			want: api.ValidationOff,
+			err:  nil,
+		},
+		{
+			name: "unknown",
+			mode: "lenient",
+			want: api.ValidationStrict,
+			err:  &api.ValidationModeError{Mode: "lenient"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := api.ParseValidationMode(tc.mode)
+			if err != nil {
+				require.EqualError(t, err, tc.err.Error())
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidationModeError(t *testing.T) {
+	got := &api.ValidationModeError{Mode: "lenient"}
+
+	require.Equal(t, got.Error(), "unknown validation mode: lenient")
+}
@@ -1,8 +1,68 @@
 package config
 
+import "time"
+
 // Server is struct for Server
 type Server struct {
 	// Path to OpenAPI specification
 	Path string
 	Port string
+	// Validate request body for any method which declares it
+	ValidateAnyMethodBody bool
+	// Handling of duplicate keys in request body: ignore, warn or strict
+	DuplicateKeys string
+	// Handling of invalid request body: strict, warn or off
+	Validation string
+	CORS       CORS
+	// Serve 200 at root path when no operation matches it
+	RootHealth bool
+	// Response body of root path health probe
+	RootHealthBody string
+	// Schemes permitted for remote specification
+	AllowedSchemes []string
+	// Hosts permitted for remote specification, any host is permitted when empty
+	AllowedHosts []string
+	// Populate response properties with same-named fields of request body
+	Echo bool
+	// Seed of generated examples, examples are random when zero
+	Seed int64
+	// Keep resources of collection endpoints in memory
+	Stateful bool
+	// Fill pagination envelopes of list responses by page and limit query parameters
+	Paginate bool
+	// Simulated latency of responses, e.g. 500ms or 100ms-500ms
+	Latency string
+	// Requests per window of each client to each path, requests are not limited when zero
+	RateLimit int
+	// Window of rate limit
+	RateLimitWindow time.Duration
+	// Probability of failure injected into responses, from 0 to 1
+	ChaosRate float64
+	// Comma-separated injected failure modes: status, truncate or slow
+	ChaosFailures string
+	// Delay of slow failure
+	ChaosDelay time.Duration
+	// URL of server receiving requests of not specified operations
+	Upstream string
+	// Timeout of requests proxied to upstream server, no timeout when zero
+	UpstreamTimeout time.Duration
+	// Reload specification on change of its file
+	Watch bool
+	// Fail on response examples not matching their schemas
+	Strict bool
+	// Write matching of each request to operation as JSON lines to stdout
+	MatchLog bool
+	// Prefixes of operation paths, paths of servers URLs of specification are used when empty
+	BasePaths []string
+	// Respond to failed requests with bare status codes and JSON bodies of validation errors
+	// instead of problem details
+	LegacyErrors bool
+}
+
+// CORS is struct for global CORS configuration.
+// CORS headers are emitted only when origins are specified.
+type CORS struct {
+	Origins []string
+	Methods []string
+	Headers []string
 }
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// GzipMinSize is minimum size of response body compressed by Gzip, smaller bodies are sent as is
+const GzipMinSize = 1024
+
+// gzipResponseWriter buffers body until it is large enough to be compressed, then body is streamed
+// through gzip writer. Smaller bodies are sent as is on close.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+	status  int
+	buf     bytes.Buffer
+	// started reports whether status code is written, gz is nil for body sent as is
+	started bool
+	gz      *gzip.Writer
+}
+
+// WriteHeader is deferred until encoding is chosen, since encoding depends on body size
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.started {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+
+		return w.ResponseWriter.Write(b)
+	}
+
+	n, _ := w.buf.Write(b)
+	if w.buf.Len() < w.minSize {
+		return n, nil
+	}
+
+	if err := w.start(true); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Flush sends buffered body to client, body is compressed since its size is unknown yet
+func (w *gzipResponseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if !w.started {
+		if err := w.start(true); err != nil {
+			return
+		}
+	}
+
+	if w.gz != nil {
+		if err := w.gz.Flush(); err != nil {
+			return
+		}
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// start writes status code and buffered body, compressed when compress is set and response is compressible
+func (w *gzipResponseWriter) start(compress bool) error {
+	w.started = true
+
+	// response depends on Accept-Encoding even when it is sent as is, so caches must not reuse it for other clients
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	if compress && w.compressible() {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	var err error
+
+	if w.gz != nil {
+		_, err = w.gz.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+
+	w.buf.Reset()
+
+	return err
+}
+
+// compressible reports whether response can be compressed: it has body which is not encoded already
+// and is not range of representation, since range is of uncompressed body
+func (w *gzipResponseWriter) compressible() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+
+	return w.Header().Get("Content-Encoding") == "" && w.Header().Get("Content-Range") == ""
+}
+
+// close writes status code and body smaller than minSize as is, or completes compressed body
+func (w *gzipResponseWriter) close() error {
+	if w.status == 0 {
+		return nil
+	}
+
+	if !w.started {
+		return w.start(false)
+	}
+
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+
+	return nil
+}
+
+// Gzip compresses response bodies of at least minSize bytes for clients accepting gzip encoding
+func Gzip(next http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+
+		next.ServeHTTP(gw, r)
+
+		// response is partially written already, nothing else can be done
+		_ = gw.close()
+	})
+}
+
+// acceptsGzip reports whether Accept-Encoding header value allows gzip, e.g. `gzip, deflate` or `*`
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		rejected := false
+
+		for _, p := range params[1:] {
+			p = strings.ReplaceAll(p, " ", "")
+			if p == "q=0" || strings.HasPrefix(p, "q=0.") && strings.Trim(p[len("q=0."):], "0") == "" {
+				rejected = true
+			}
+		}
+
+		if !rejected {
+			return true
+		}
+	}
+
+	return false
+}
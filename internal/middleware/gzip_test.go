@@ -0,0 +1,209 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/middleware"
+)
+
+func TestGzip(t *testing.T) {
+	large := "[" + strings.Repeat(`{"name":"Elon"},`, 200) + `{"name":"Elon"}]`
+	small := `{"name":"Elon"}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		status         int
+		contentRange   string
+		body           string
+		compressed     bool
+		vary           bool
+	}{
+		{
+			name:           "large body",
+			acceptEncoding: "gzip, deflate",
+			body:           large,
+			compressed:     true,
+			vary:           true,
+		},
+		{
+			name:           "any encoding",
+			acceptEncoding: "*",
+			body:           large,
+			compressed:     true,
+			vary:           true,
+		},
+		{
+			name:           "small body",
+			acceptEncoding: "gzip",
+			body:           small,
+			compressed:     false,
+			vary:           true,
+		},
+		{
+			name:           "partial content",
+			acceptEncoding: "gzip",
+			status:         http.StatusPartialContent,
+			contentRange:   "bytes 0-3216/10000",
+			body:           large,
+			compressed:     false,
+			vary:           true,
+		},
+		{
+			name:           "content range",
+			acceptEncoding: "gzip",
+			contentRange:   "bytes */10000",
+			body:           large,
+			compressed:     false,
+			vary:           true,
+		},
+		{
+			name:           "not modified",
+			acceptEncoding: "gzip",
+			status:         http.StatusNotModified,
+			compressed:     false,
+			vary:           true,
+		},
+		{
+			name:           "no accept encoding",
+			acceptEncoding: "",
+			body:           large,
+			compressed:     false,
+		},
+		{
+			name:           "gzip rejected",
+			acceptEncoding: "gzip;q=0, deflate",
+			body:           large,
+			compressed:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status := tc.status
+			if status == 0 {
+				status = http.StatusCreated
+			}
+
+			h := middleware.Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if tc.contentRange != "" {
+					w.Header().Set("Content-Range", tc.contentRange)
+				}
+
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(tc.body))
+			}), middleware.GzipMinSize)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			r.Header.Set("Accept-Encoding", tc.acceptEncoding)
+
+			h.ServeHTTP(w, r)
+
+			require.Equal(t, status, w.Code)
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			if tc.vary {
+				require.Equal(t, []string{"Accept-Encoding"}, w.Header().Values("Vary"))
+			} else {
+				require.Empty(t, w.Header().Values("Vary"))
+			}
+
+			if !tc.compressed {
+				require.Empty(t, w.Header().Get("Content-Encoding"))
+				require.Equal(t, tc.body, w.Body.String())
+
+				return
+			}
+
+			require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+			require.Less(t, w.Body.Len(), len(tc.body))
+
+			gz, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			require.Equal(t, tc.body, string(body))
+		})
+	}
+}
+
+func TestGzip_Stream(t *testing.T) {
+	chunk := strings.Repeat(`{"name":"Elon"},`, 100)
+
+	tests := []struct {
+		name    string
+		chunks  int
+		minSize int
+		flush   bool
+	}{
+		{
+			name:    "body larger than min size",
+			chunks:  50,
+			minSize: 1000,
+		},
+		{
+			name:    "flushed body smaller than min size",
+			chunks:  1,
+			minSize: middleware.GzipMinSize * 10,
+			flush:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffered []int
+
+			w := httptest.NewRecorder()
+
+			h := middleware.Gzip(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				for i := 0; i < tc.chunks; i++ {
+					_, _ = rw.Write([]byte(chunk[:len(chunk)/2]))
+					_, _ = rw.Write([]byte(chunk[len(chunk)/2:]))
+
+					// body is recorded while it is written, instead of being written at once
+					buffered = append(buffered, w.Body.Len())
+				}
+
+				f, ok := rw.(http.Flusher)
+				require.True(t, ok)
+
+				if tc.flush {
+					require.Zero(t, w.Body.Len())
+
+					f.Flush()
+
+					require.True(t, w.Flushed)
+					require.NotZero(t, w.Body.Len())
+				} else {
+					require.NotZero(t, buffered[0])
+				}
+			}), tc.minSize)
+
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			r.Header.Set("Accept-Encoding", "gzip")
+
+			h.ServeHTTP(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+			gz, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			require.Equal(t, strings.Repeat(chunk, tc.chunks), string(body))
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package openapi contains types of OpenAPI documents decoded by dummy.
+//
+// Package is derived from github.com/neotoolkit/openapi v0.3.1. Types are kept here rather than imported,
+// so fields of extensions dummy reads, e.g. `x-` fields of examples, are declared on the types themselves
+// instead of being decoded by second pass over each document.
+package openapi
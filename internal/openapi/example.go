@@ -0,0 +1,70 @@
+package openapi
+
+import "sort"
+
+// Examples -.
+type Examples map[string]Example
+
+// GetKeys returns sorted names of examples
+func (e Examples) GetKeys() []string {
+	keys := make([]string, 0, len(e))
+
+	for key := range e {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// DefaultKey returns name of default example: first by name among examples flagged by `x-default`,
+// otherwise first by name. Empty name is returned when there are no examples.
+func (e Examples) DefaultKey() string {
+	keys := e.GetKeys()
+
+	for _, key := range keys {
+		if e[key].Default {
+			return key
+		}
+	}
+
+	if len(keys) == 0 {
+		return ""
+	}
+
+	return keys[0]
+}
+
+// Example -.
+type Example struct {
+	Summary       string      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description   string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Value         interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	ExternalValue string      `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
+	// Weight of example for weighted selection among examples of a response
+	Weight float64 `json:"x-dummy-weight,omitempty" yaml:"x-dummy-weight,omitempty"`
+	// Default flags example used when client does not choose one
+	Default bool `json:"x-default,omitempty" yaml:"x-default,omitempty"`
+}
+
+// ExampleToResponse converts example value to response value
+func ExampleToResponse(data interface{}) interface{} {
+	d, ok := data.([]interface{})
+	if !ok {
+		return data
+	}
+
+	res := make([]map[string]interface{}, len(d))
+
+	for k, v := range d {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return data
+		}
+
+		res[k] = m
+	}
+
+	return res
+}
@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// JSONError is syntax error of JSON document at position
+type JSONError struct {
+	// Line and Column are numbered from 1
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error -.
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("invalid JSON at line %d, column %d: %s", e.Line, e.Column, e.Err)
+}
+
+// Unwrap -.
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// unmarshalJSON decodes JSON like YAML decoder does: integers are decoded as int64 or uint64 without loss
+// of precision of large numbers, syntax errors are returned as JSONError
+func unmarshalJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// offset of syntax error is offset after invalid character
+			return jsonError(data, syntaxErr.Offset-1, err)
+		}
+
+		return err
+	}
+
+	// content after document is not JSON document, e.g. second object
+	if rest := bytes.TrimLeft(data[dec.InputOffset():], " \t\r\n"); len(rest) > 0 {
+		return jsonError(data, int64(len(data)-len(rest)), errors.New("unexpected content after JSON document"))
+	}
+
+	numbers(reflect.ValueOf(v))
+
+	return nil
+}
+
+// jsonError returns JSONError of character at offset of data
+func jsonError(data []byte, offset int64, err error) error {
+	switch {
+	case offset < 0:
+		offset = 0
+	case offset > int64(len(data)):
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+
+	return &JSONError{Line: line, Column: column, Err: err}
+}
+
+// numbers replaces json.Number values of v with int64, uint64 or float64 ones
+func numbers(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		if n, ok := v.Interface().(json.Number); ok {
+			if v.CanSet() {
+				v.Set(reflect.ValueOf(number(n)))
+			}
+
+			return
+		}
+
+		numbers(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				numbers(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			numbers(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// map values are not addressable, so value is replaced by its copy
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			numbers(value)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
+
+// number returns value of JSON number, integers are int64 and uint64 like ones of YAML decoder
+func number(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return u
+	}
+
+	f, _ := n.Float64()
+
+	return f
+}
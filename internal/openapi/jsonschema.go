@@ -0,0 +1,122 @@
+package openapi
+
+// jsonSchema has keywords of OpenAPI 3.1 schema, i.e. JSON Schema, which differ from OpenAPI 3.0
+type jsonSchema struct {
+	Type             interface{} `json:"type" yaml:"type"`
+	Const            interface{} `json:"const" yaml:"const"`
+	Examples         interface{} `json:"examples" yaml:"examples"`
+	ExclusiveMinimum interface{} `json:"exclusiveMinimum" yaml:"exclusiveMinimum"`
+	ExclusiveMaximum interface{} `json:"exclusiveMaximum" yaml:"exclusiveMaximum"`
+}
+
+// compatible reports whether schema is decoded as is, i.e. it has no keywords of OpenAPI 3.1
+func (j jsonSchema) compatible() bool {
+	_, types := j.Type.([]interface{})
+	_, examples := j.Examples.([]interface{})
+
+	return !types && !examples && nil == j.Const && isBool(j.ExclusiveMinimum) && isBool(j.ExclusiveMaximum)
+}
+
+func isBool(v interface{}) bool {
+	_, ok := v.(bool)
+
+	return ok || nil == v
+}
+
+// unmarshal decodes schema, keywords of OpenAPI 3.1 are rewritten to OpenAPI 3.0 counterparts:
+//   - list of types, e.g. `type: [string, "null"]`, gets first non-null type and null in list makes it nullable
+//   - `const` is enum of single value
+//   - first of `examples` is example
+//   - numeric `exclusiveMinimum` and `exclusiveMaximum` are exclusive `minimum` and `maximum`
+func (s *Schema) unmarshal(
+	decode func(interface{}) error,
+	unmarshal func([]byte, interface{}) error,
+	marshal func(interface{}) ([]byte, error),
+) error {
+	var probe jsonSchema
+
+	if err := decode(&probe); err != nil {
+		return err
+	}
+
+	if probe.compatible() {
+		return decode((*schema)(s))
+	}
+
+	var fields map[string]interface{}
+	if err := decode(&fields); err != nil {
+		return err
+	}
+
+	// type is set after decoding, since marshaled `null` type would be decoded as absent one
+	typ, _ := probe.Type.(string)
+
+	if types, ok := probe.Type.([]interface{}); ok {
+		typ = typeOf(types)
+
+		if len(types) > 1 && hasNull(types) {
+			fields["nullable"] = true
+		}
+	}
+
+	if probe.Const != nil {
+		fields["enum"] = []interface{}{probe.Const}
+		delete(fields, "const")
+	}
+
+	if examples, ok := probe.Examples.([]interface{}); ok {
+		if _, ok := fields["example"]; !ok && len(examples) > 0 {
+			fields["example"] = examples[0]
+		}
+
+		delete(fields, "examples")
+	}
+
+	delete(fields, "type")
+
+	for bound, exclusive := range map[string]string{"minimum": "exclusiveMinimum", "maximum": "exclusiveMaximum"} {
+		if isBool(fields[exclusive]) {
+			continue
+		}
+
+		fields[bound], fields[exclusive] = fields[exclusive], true
+	}
+
+	data, err := marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	if err := unmarshal(data, (*schema)(s)); err != nil {
+		return err
+	}
+
+	s.Type = typ
+
+	return nil
+}
+
+// typeOf returns first non-null type of list, `null` is type of list without other types
+func typeOf(types []interface{}) string {
+	for _, t := range types {
+		if name, _ := t.(string); name != "null" {
+			return name
+		}
+	}
+
+	if hasNull(types) {
+		return "null"
+	}
+
+	return ""
+}
+
+func hasNull(types []interface{}) bool {
+	for _, t := range types {
+		if t == "null" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package openapi
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// OpenAPI is the root document object of the OpenAPI document
+type OpenAPI struct {
+	OpenAPI    string     `json:"openapi" yaml:"openapi"`
+	Info       Info       `json:"info" yaml:"info"`
+	Servers    Servers    `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      Paths      `json:"paths" yaml:"paths"`
+	Components Components `json:"components,omitempty" yaml:"components,omitempty"`
+	// Security is default security requirements of operations
+	Security SecurityRequirements `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+// Info -.
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+// Servers -.
+type Servers []Server
+
+// Server -.
+type Server struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Variables substitute their names in braces of URL, e.g. `/{version}`
+	Variables map[string]ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// ServerVariable -.
+type ServerVariable struct {
+	Default     string   `json:"default" yaml:"default"`
+	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Expand returns URL of server with variables substituted by their default values
+func (s Server) Expand() string {
+	res := s.URL
+
+	for name, v := range s.Variables {
+		res = strings.ReplaceAll(res, "{"+name+"}", v.Default)
+	}
+
+	return res
+}
+
+// Components -.
+type Components struct {
+	Schemas         Schemas         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	SecuritySchemes SecuritySchemes `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+// SchemaError -.
+type SchemaError struct {
+	Ref string
+}
+
+// Error -.
+func (e *SchemaError) Error() string {
+	return "unknown schema " + e.Ref
+}
+
+const schemasRefPrefix = "#/components/schemas/"
+
+// LookupByReference returns schema by reference
+func (api OpenAPI) LookupByReference(ref string) (Schema, error) {
+	if !strings.HasPrefix(ref, schemasRefPrefix) {
+		return Schema{}, &SchemaError{Ref: ref}
+	}
+
+	schema, ok := api.Components.Schemas[strings.TrimPrefix(ref, schemasRefPrefix)]
+	if !ok || nil == schema {
+		return Schema{}, &SchemaError{Ref: ref}
+	}
+
+	return *schema, nil
+}
+
+// Parse returns OpenAPI document from specification file content.
+// JSON content is detected by leading `{` and decoded by JSON decoder, other content is decoded as YAML
+// with aliases of anchors and merge keys, e.g. `<<: *base`, expanded.
+// Swagger 2.0 document is converted to OpenAPI document.
+func Parse(file []byte) (OpenAPI, error) {
+	if IsJSON(file) {
+		return ParseJSON(file)
+	}
+
+	file, err := ExpandAliases(file)
+	if err != nil {
+		return OpenAPI{}, err
+	}
+
+	return parse(file, yaml.Unmarshal)
+}
+
+// ParseJSON returns OpenAPI document from JSON specification file content, numbers are decoded without loss of
+// precision and syntax errors are JSONError with position
+func ParseJSON(file []byte) (OpenAPI, error) {
+	return parse(file, unmarshalJSON)
+}
+
+func parse(file []byte, unmarshal func(data []byte, v interface{}) error) (OpenAPI, error) {
+	var version struct {
+		Swagger string `json:"swagger" yaml:"swagger"`
+	}
+
+	if err := unmarshal(file, &version); err != nil {
+		return OpenAPI{}, err
+	}
+
+	if version.Swagger == SwaggerVersion {
+		var swagger Swagger
+
+		if err := unmarshal(file, &swagger); err != nil {
+			return OpenAPI{}, err
+		}
+
+		return swagger.OpenAPI(), nil
+	}
+
+	var openapi OpenAPI
+
+	if err := unmarshal(file, &openapi); err != nil {
+		return OpenAPI{}, err
+	}
+
+	return openapi, nil
+}
+
+// IsJSON reports whether content is JSON object
+func IsJSON(file []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(file), []byte("{"))
+}
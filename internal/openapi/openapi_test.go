@@ -0,0 +1,618 @@
+package openapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+func TestSchemaError(t *testing.T) {
+	got := &openapi.SchemaError{
+		Ref: "#/components/schemas/User",
+	}
+
+	require.Equal(t, got.Error(), "unknown schema #/components/schemas/User")
+}
+
+func TestServer_Expand(t *testing.T) {
+	tests := []struct {
+		name   string
+		server openapi.Server
+		want   string
+	}{
+		{
+			name:   "no variables",
+			server: openapi.Server{URL: "https://api.example.com/v1"},
+			want:   "https://api.example.com/v1",
+		},
+		{
+			name: "variables",
+			server: openapi.Server{
+				URL: "https://{host}/{version}/{version}",
+				Variables: map[string]openapi.ServerVariable{
+					"host":    {Default: "api.example.com"},
+					"version": {Default: "v2", Enum: []string{"v1", "v2"}},
+				},
+			},
+			want: "https://api.example.com/v2/v2",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.server.Expand())
+		})
+	}
+}
+
+func TestOpenAPI_LookupByReference(t *testing.T) {
+	api := openapi.OpenAPI{
+		Components: openapi.Components{
+			Schemas: openapi.Schemas{
+				"User": {
+					Type: "object",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want openapi.Schema
+		err  error
+	}{
+		{
+			name: "schema",
+			ref:  "#/components/schemas/User",
+			want: openapi.Schema{Type: "object"},
+			err:  nil,
+		},
+		{
+			name: "unknown schema",
+			ref:  "#/components/schemas/Users",
+			want: openapi.Schema{},
+			err:  &openapi.SchemaError{Ref: "#/components/schemas/Users"},
+		},
+		{
+			name: "wrong reference",
+			ref:  "wrong schema reference",
+			want: openapi.Schema{},
+			err:  &openapi.SchemaError{Ref: "wrong schema reference"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := api.LookupByReference(tc.ref)
+			if err != nil {
+				require.EqualError(t, err, tc.err.Error())
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExamples_DefaultKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		examples openapi.Examples
+		want     string
+	}{
+		{
+			name:     "no examples",
+			examples: nil,
+			want:     "",
+		},
+		{
+			name:     "first by name",
+			examples: openapi.Examples{"second": {}, "first": {}, "third": {}},
+			want:     "first",
+		},
+		{
+			name:     "flagged",
+			examples: openapi.Examples{"second": {Default: true}, "first": {}, "third": {Default: true}},
+			want:     "second",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				require.Equal(t, tc.want, tc.examples.DefaultKey())
+			}
+		})
+	}
+}
+
+func TestExampleToResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want interface{}
+	}{
+		{
+			name: "nil",
+			data: nil,
+			want: nil,
+		},
+		{
+			name: "object",
+			data: map[string]interface{}{"key": "value"},
+			want: map[string]interface{}{"key": "value"},
+		},
+		{
+			name: "array of objects",
+			data: []interface{}{map[string]interface{}{"key": "value"}},
+			want: []map[string]interface{}{{"key": "value"}},
+		},
+		{
+			name: "array of strings",
+			data: []interface{}{"a", "b"},
+			want: []interface{}{"a", "b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := openapi.ExampleToResponse(tc.data)
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	file := []byte(`
+openapi: 3.0.3
+info:
+  title: Test dummy API
+  version: 0.1.0
+paths:
+  /healthz:
+    get:
+      responses:
+        '200':
+          description: ''
+          content:
+            application/json:
+              examples:
+                ok:
+                  x-dummy-weight: 2
+                  value:
+                    status: ok
+`)
+
+	got, err := openapi.Parse(file)
+	require.NoError(t, err)
+
+	require.Equal(t, "3.0.3", got.OpenAPI)
+	require.Equal(t, "Test dummy API", got.Info.Title)
+
+	example := got.Paths["/healthz"].Get.Responses["200"].Content["application/json"].Examples["ok"]
+
+	require.Equal(t, 2.0, example.Weight)
+	require.Equal(t, map[string]interface{}{"status": "ok"}, example.Value)
+
+	_, err = openapi.Parse([]byte("openapi: ["))
+	require.Error(t, err)
+}
+
+func TestParse_Swagger(t *testing.T) {
+	file := []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Test dummy API", "version": "0.1.0"},
+  "paths": {
+    "/users": {
+      "post": {
+        "consumes": ["application/xml"],
+        "parameters": [
+          {"in": "query", "name": "dry", "required": true, "type": "boolean"},
+          {"in": "body", "name": "user", "schema": {"$ref": "#/definitions/User"}}
+        ],
+        "responses": {
+          "201": {"description": "", "schema": {"type": "array", "items": {"$ref": "#/definitions/User"}}},
+          "204": {"description": ""}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "properties": {"org": {"$ref": "#/definitions/Org"}}},
+    "Org": {"type": "object"}
+  }
+}`)
+
+	got, err := openapi.Parse(file)
+	require.NoError(t, err)
+
+	require.Equal(t, "3.0.3", got.OpenAPI)
+	require.Equal(t, "Test dummy API", got.Info.Title)
+	require.Equal(t, "#/components/schemas/Org", got.Components.Schemas["User"].Properties["org"].Ref)
+
+	post := got.Paths["/users"].Post
+
+	require.Equal(t, openapi.Parameters{
+		{Name: "dry", In: "query", Required: true, Schema: &openapi.Schema{Type: "boolean"}},
+	}, post.Parameters)
+	require.Equal(t, "#/components/schemas/User", post.RequestBody.Content["application/xml"].Schema.Ref)
+	require.Equal(t, "#/components/schemas/User", post.Responses["201"].Content["application/json"].Schema.Items.Ref)
+	require.Empty(t, post.Responses["204"].Content)
+}
+
+func TestParse_SwaggerShared(t *testing.T) {
+	file := []byte(`
+swagger: "2.0"
+info:
+  title: Test dummy API
+  version: 0.1.0
+basePath: /v1
+securityDefinitions:
+  basic:
+    type: basic
+  key:
+    type: apiKey
+    in: header
+    name: X-API-Key
+security:
+  - key: []
+parameters:
+  limit:
+    in: query
+    name: limit
+    type: integer
+responses:
+  NotFound:
+    description: Not found
+    schema:
+      $ref: '#/definitions/Error'
+paths:
+  /users:
+    get:
+      parameters:
+        - $ref: '#/parameters/limit'
+      responses:
+        '200':
+          description: ''
+          headers:
+            X-Total-Count:
+              type: integer
+        '404':
+          $ref: '#/responses/NotFound'
+    post:
+      security:
+        - basic: []
+      parameters:
+        - in: formData
+          name: name
+          required: true
+          type: string
+        - in: formData
+          name: avatar
+          type: file
+      responses:
+        '201':
+          description: ''
+definitions:
+  Error:
+    type: object
+`)
+
+	got, err := openapi.Parse(file)
+	require.NoError(t, err)
+
+	require.Equal(t, openapi.Servers{{URL: "/v1"}}, got.Servers)
+	require.Equal(t, openapi.SecurityRequirements{{"key": {}}}, got.Security)
+	require.Equal(t, &openapi.SecurityScheme{Type: "http", Scheme: "basic"}, got.Components.SecuritySchemes["basic"])
+	require.Equal(t, &openapi.SecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"}, got.Components.SecuritySchemes["key"])
+
+	get := got.Paths["/users"].Get
+
+	require.Equal(t, openapi.Parameters{
+		{Name: "limit", In: "query", Schema: &openapi.Schema{Type: "integer"}},
+	}, get.Parameters)
+	require.Equal(t, &openapi.Schema{Type: "integer"}, get.Responses["200"].Headers["X-Total-Count"].Schema)
+	require.Equal(t, "Not found", get.Responses["404"].Description)
+	require.Equal(t, "#/components/schemas/Error", get.Responses["404"].Content["application/json"].Schema.Ref)
+
+	post := got.Paths["/users"].Post
+
+	require.Equal(t, &openapi.SecurityRequirements{{"basic": {}}}, post.Security)
+	require.True(t, post.RequestBody.Required)
+	require.Equal(t, openapi.Content{
+		"multipart/form-data": {
+			Schema: openapi.Schema{
+				Type: "object",
+				Properties: openapi.Schemas{
+					"name":   {Type: "string"},
+					"avatar": {Type: "string", Format: "binary"},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}, post.RequestBody.Content)
+}
+
+func TestParse_AdditionalProperties(t *testing.T) {
+	yml := []byte(`
+openapi: 3.0.3
+components:
+  schemas:
+    Free:
+      type: object
+      additionalProperties: true
+    Strict:
+      type: object
+      additionalProperties: false
+    Map:
+      type: object
+      additionalProperties:
+        type: integer
+    Object:
+      type: object
+`)
+
+	json := []byte(`{
+  "openapi": "3.0.3",
+  "components": {
+    "schemas": {
+      "Free": {"type": "object", "additionalProperties": true},
+      "Strict": {"type": "object", "additionalProperties": false},
+      "Map": {"type": "object", "additionalProperties": {"type": "integer"}},
+      "Object": {"type": "object"}
+    }
+  }
+}`)
+
+	want := map[string]*openapi.AdditionalProperties{
+		"Free":   {Allowed: true},
+		"Strict": {Allowed: false},
+		"Map":    {Allowed: true, Schema: &openapi.Schema{Type: "integer"}},
+		"Object": nil,
+	}
+
+	for _, file := range [][]byte{yml, json} {
+		got, err := openapi.Parse(file)
+		require.NoError(t, err)
+
+		for name, additional := range want {
+			require.Equal(t, additional, got.Components.Schemas[name].AdditionalProperties, name)
+		}
+	}
+}
+
+func TestParse_TypeList(t *testing.T) {
+	yml := []byte(`
+openapi: 3.1.0
+components:
+  schemas:
+    Name:
+      type: ["string", "null"]
+      example: Elon
+    User:
+      type: object
+      properties:
+        age:
+          type: ["null", "integer"]
+    Tag:
+      type: [string]
+`)
+
+	json := []byte(`{
+  "openapi": "3.1.0",
+  "components": {
+    "schemas": {
+      "Name": {"type": ["string", "null"], "example": "Elon"},
+      "User": {"type": "object", "properties": {"age": {"type": ["null", "integer"]}}},
+      "Tag": {"type": ["string"]}
+    }
+  }
+}`)
+
+	for _, file := range [][]byte{yml, json} {
+		got, err := openapi.Parse(file)
+		require.NoError(t, err)
+
+		schemas := got.Components.Schemas
+
+		require.Equal(t, &openapi.Schema{Type: "string", Nullable: true, Example: "Elon"}, schemas["Name"])
+		require.Equal(t, &openapi.Schema{Type: "integer", Nullable: true}, schemas["User"].Properties["age"])
+		require.Equal(t, &openapi.Schema{Type: "string"}, schemas["Tag"])
+	}
+}
+
+func TestParse_JSONSchemaKeywords(t *testing.T) {
+	yml := []byte(`
+openapi: 3.1.0
+components:
+  schemas:
+    Kind:
+      const: user
+    Age:
+      type: integer
+      exclusiveMinimum: 17
+      exclusiveMaximum: 150
+      examples: [42, 18]
+    Point:
+      type: array
+      prefixItems:
+        - type: number
+        - type: number
+    Nothing:
+      type: ["null"]
+`)
+
+	got, err := openapi.Parse(yml)
+	require.NoError(t, err)
+
+	minimum, maximum := 17.0, 150.0
+	schemas := got.Components.Schemas
+
+	require.Equal(t, &openapi.Schema{Enum: []interface{}{"user"}}, schemas["Kind"])
+	require.Equal(t, &openapi.Schema{
+		Type:             "integer",
+		Example:          uint64(42),
+		Minimum:          &minimum,
+		Maximum:          &maximum,
+		ExclusiveMinimum: true,
+		ExclusiveMaximum: true,
+	}, schemas["Age"])
+	require.Equal(t, &openapi.Schema{
+		Type:        "array",
+		PrefixItems: []*openapi.Schema{{Type: "number"}, {Type: "number"}},
+	}, schemas["Point"])
+	require.Equal(t, &openapi.Schema{Type: "null"}, schemas["Nothing"])
+}
+
+func TestJSONError(t *testing.T) {
+	got := &openapi.JSONError{
+		Line:   3,
+		Column: 7,
+		Err:    errors.New("invalid character '}'"),
+	}
+
+	require.EqualError(t, got, "invalid JSON at line 3, column 7: invalid character '}'")
+}
+
+func TestParseJSON(t *testing.T) {
+	file := []byte("{\n\t\"openapi\": \"3.0.3\",\n\t\"components\": {\"schemas\": {\"ID\": {\"type\": \"integer\", " +
+		"\"example\": 9007199254740993, \"enum\": [18446744073709551615, 1.5]}}}\n}")
+
+	got, err := openapi.ParseJSON(file)
+	require.NoError(t, err)
+
+	require.Equal(t, &openapi.Schema{
+		Type:    "integer",
+		Example: int64(9007199254740993),
+		Enum:    []interface{}{uint64(18446744073709551615), 1.5},
+	}, got.Components.Schemas["ID"])
+}
+
+func TestParseJSON_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want *openapi.JSONError
+	}{
+		{
+			name: "syntax",
+			file: "{\n  \"openapi\": \"3.0.3\",\n  \"paths\": {,}\n}",
+			want: &openapi.JSONError{Line: 3, Column: 13},
+		},
+		{
+			name: "content after document",
+			file: "{\"openapi\": \"3.0.3\"}\n{}",
+			want: &openapi.JSONError{Line: 2, Column: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := openapi.ParseJSON([]byte(tc.file))
+
+			var got *openapi.JSONError
+
+			require.ErrorAs(t, err, &got)
+			require.Equal(t, tc.want.Line, got.Line)
+			require.Equal(t, tc.want.Column, got.Column)
+		})
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want map[string]interface{}
+	}{
+		{
+			name: "alias followed by quoted key",
+			file: "base: &base\n  a: 1\nr:\n  '200': *base\n  '404':\n    <<: *base\n    b: 2\n",
+			want: map[string]interface{}{
+				"base": map[string]interface{}{"a": uint64(1)},
+				"r": map[string]interface{}{
+					"200": map[string]interface{}{"a": uint64(1)},
+					"404": map[string]interface{}{"a": uint64(1), "b": uint64(2)},
+				},
+			},
+		},
+		{
+			name: "key takes precedence over merged one",
+			file: "base: &base\n  a: 1\n  b: 1\nr:\n  a: 2\n  <<: *base\n",
+			want: map[string]interface{}{
+				"base": map[string]interface{}{"a": uint64(1), "b": uint64(1)},
+				"r":    map[string]interface{}{"a": uint64(2), "b": uint64(1)},
+			},
+		},
+		{
+			name: "earlier merged mapping takes precedence",
+			file: "x: &x\n  a: 1\ny: &y\n  a: 2\n  b: 2\nr:\n  <<: [*x, *y]\n",
+			want: map[string]interface{}{
+				"x": map[string]interface{}{"a": uint64(1)},
+				"y": map[string]interface{}{"a": uint64(2), "b": uint64(2)},
+				"r": map[string]interface{}{"a": uint64(1), "b": uint64(2)},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := openapi.ExpandAliases([]byte(tc.file))
+			require.NoError(t, err)
+
+			var doc map[string]interface{}
+
+			require.NoError(t, yaml.Unmarshal(got, &doc))
+			require.Equal(t, tc.want, doc)
+		})
+	}
+
+	t.Run("without aliases", func(t *testing.T) {
+		file := []byte("a: &a 1\n")
+
+		got, err := openapi.ExpandAliases(file)
+		require.NoError(t, err)
+		require.Equal(t, file, got)
+	})
+}
+
+func TestIsJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want bool
+	}{
+		{
+			name: "json",
+			file: `{"openapi": "3.0.3"}`,
+			want: true,
+		},
+		{
+			name: "json with leading whitespace",
+			file: "\n  {\"openapi\": \"3.0.3\"}",
+			want: true,
+		},
+		{
+			name: "yaml",
+			file: "openapi: 3.0.3",
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, openapi.IsJSON([]byte(tc.file)))
+		})
+	}
+}
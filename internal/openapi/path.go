@@ -0,0 +1,110 @@
+package openapi
+
+// Paths -.
+type Paths map[string]*Path
+
+// Path -.
+type Path struct {
+	Summary     string     `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Get         *Operation `json:"get,omitempty" yaml:"get,omitempty"`
+	Put         *Operation `json:"put,omitempty" yaml:"put,omitempty"`
+	Post        *Operation `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete      *Operation `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Options     *Operation `json:"options,omitempty" yaml:"options,omitempty"`
+	Head        *Operation `json:"head,omitempty" yaml:"head,omitempty"`
+	Patch       *Operation `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Trace       *Operation `json:"trace,omitempty" yaml:"trace,omitempty"`
+	Parameters  Parameters `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// Operation -.
+type Operation struct {
+	Tags        []string    `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	OperationID string      `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  Parameters  `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody RequestBody `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   Responses   `json:"responses" yaml:"responses"`
+	// Security overrides default security requirements when not nil, empty requirements disable security
+	Security *SecurityRequirements `json:"security,omitempty" yaml:"security,omitempty"`
+	// CORS overrides global CORS configuration for operation
+	CORS *CORS `json:"x-dummy-cors,omitempty" yaml:"x-dummy-cors,omitempty"`
+	// RateLimit overrides global rate limit for operation
+	RateLimit *RateLimit `json:"x-rate-limit,omitempty" yaml:"x-rate-limit,omitempty"`
+}
+
+// RateLimit allows requests per window, e.g. `{requests: 10, window: 1m}`
+type RateLimit struct {
+	Requests int    `json:"requests" yaml:"requests"`
+	Window   string `json:"window,omitempty" yaml:"window,omitempty"`
+}
+
+// CORS -.
+type CORS struct {
+	Origins []string `json:"origins,omitempty" yaml:"origins,omitempty"`
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Headers []string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// Parameters -.
+type Parameters []Parameter
+
+// Parameter -.
+type Parameter struct {
+	Name        string      `json:"name" yaml:"name"`
+	In          string      `json:"in" yaml:"in"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool        `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *Schema     `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example     interface{} `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples    Examples    `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// RequestBody -.
+type RequestBody struct {
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Content     Content `json:"content" yaml:"content"`
+	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// Responses -.
+type Responses map[string]*Response
+
+// Response -.
+type Response struct {
+	Description string  `json:"description" yaml:"description"`
+	Headers     Headers `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     Content `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// Headers -.
+type Headers map[string]*Header
+
+// Header -.
+type Header struct {
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool        `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *Schema     `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example     interface{} `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples    Examples    `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// Content -.
+type Content map[string]*MediaType
+
+// MediaType -.
+type MediaType struct {
+	Schema   Schema      `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example  interface{} `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples Examples    `json:"examples,omitempty" yaml:"examples,omitempty"`
+	// Encoding describes encoding of properties of multipart or URL-encoded form body, by property name
+	Encoding map[string]Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+// Encoding -.
+type Encoding struct {
+	// ContentType is comma-separated list of allowed content types of multipart part, e.g. `image/png, image/jpeg`
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+}
@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"encoding/json"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Schemas -.
+type Schemas map[string]*Schema
+
+// Schema -.
+type Schema struct {
+	Properties Schemas       `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Items      *Schema       `json:"items,omitempty" yaml:"items,omitempty"`
+	Type       string        `json:"type,omitempty" yaml:"type,omitempty"`
+	Format     string        `json:"format,omitempty" yaml:"format,omitempty"`
+	Default    interface{}   `json:"default,omitempty" yaml:"default,omitempty"`
+	Example    interface{}   `json:"example,omitempty" yaml:"example,omitempty"`
+	Enum       []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Faker      string        `json:"x-faker,omitempty" yaml:"x-faker,omitempty"`
+	Nullable   bool          `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	XML        *XML          `json:"xml,omitempty" yaml:"xml,omitempty"`
+	Required   []string      `json:"required,omitempty" yaml:"required,omitempty"`
+	Ref        string        `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	AllOf      []*Schema     `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	OneOf      []*Schema     `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf      []*Schema     `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	// PrefixItems of OpenAPI 3.1 are schemas of tuple items by position
+	PrefixItems []*Schema `json:"prefixItems,omitempty" yaml:"prefixItems,omitempty"`
+	// Discriminator identifies branch of oneOf and anyOf by value of property
+	Discriminator *Discriminator `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
+
+	AdditionalProperties *AdditionalProperties `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+
+	// ReadOnly property is sent in responses only, e.g. server-assigned identifier
+	ReadOnly bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	// WriteOnly property is sent in requests only, e.g. password
+	WriteOnly bool `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
+
+	MinProperties int `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
+	MaxProperties int `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
+	MinItems      int `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems      int `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+
+	MinLength int `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	// MaxLength is pointer, since zero maximum length allows empty string only
+	MaxLength *int   `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
+
+// schema has fields of Schema without its unmarshalers
+type schema Schema
+
+// UnmarshalJSON -.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	return s.unmarshal(func(v interface{}) error {
+		return unmarshalJSON(data, v)
+	}, unmarshalJSON, json.Marshal)
+}
+
+// UnmarshalYAML -.
+func (s *Schema) UnmarshalYAML(decode func(interface{}) error) error {
+	return s.unmarshal(decode, yaml.Unmarshal, yaml.Marshal)
+}
+
+// XML describes XML representation of schema
+type XML struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Attribute makes property attribute of parent element instead of child element
+	Attribute bool `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	// Wrapped wraps elements of array into element of array name
+	Wrapped bool `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+}
+
+// Discriminator -.
+type Discriminator struct {
+	PropertyName string `json:"propertyName" yaml:"propertyName"`
+	// Mapping contains references or names of schemas by property value,
+	// name of schema is property value of schema missing in mapping
+	Mapping map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+}
+
+// AdditionalProperties describes object properties not listed in properties,
+// it is either boolean or schema of property values
+type AdditionalProperties struct {
+	// Allowed is false for `additionalProperties: false`
+	Allowed bool
+	// Schema of property values, any value is allowed when nil
+	Schema *Schema
+}
+
+// UnmarshalJSON -.
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	return a.unmarshal(data, unmarshalJSON)
+}
+
+// UnmarshalYAML -.
+func (a *AdditionalProperties) UnmarshalYAML(data []byte) error {
+	return a.unmarshal(data, yaml.Unmarshal)
+}
+
+func (a *AdditionalProperties) unmarshal(data []byte, unmarshal func([]byte, interface{}) error) error {
+	var allowed bool
+	if err := unmarshal(data, &allowed); err == nil {
+		a.Allowed, a.Schema = allowed, nil
+
+		return nil
+	}
+
+	var schema Schema
+	if err := unmarshal(data, &schema); err != nil {
+		return err
+	}
+
+	a.Allowed, a.Schema = true, &schema
+
+	return nil
+}
+
+// MarshalJSON -.
+func (a AdditionalProperties) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.value())
+}
+
+// MarshalYAML -.
+func (a AdditionalProperties) MarshalYAML() (interface{}, error) {
+	return a.value(), nil
+}
+
+func (a AdditionalProperties) value() interface{} {
+	if a.Schema != nil {
+		return a.Schema
+	}
+
+	return a.Allowed
+}
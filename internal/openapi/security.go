@@ -0,0 +1,22 @@
+package openapi
+
+// SecuritySchemes -.
+type SecuritySchemes map[string]*SecurityScheme
+
+// SecurityScheme -.
+type SecurityScheme struct {
+	Type        string `json:"type" yaml:"type"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Name and In are name and location of API key: header, query or cookie
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	In   string `json:"in,omitempty" yaml:"in,omitempty"`
+	// Scheme is HTTP authorization scheme, e.g. bearer or basic
+	Scheme       string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirements are alternative security requirements, any one of them is to be satisfied
+type SecurityRequirements []SecurityRequirement
+
+// SecurityRequirement contains scopes by names of security schemes, all of them are to be satisfied
+type SecurityRequirement map[string][]string
@@ -0,0 +1,416 @@
+package openapi
+
+import "strings"
+
+// SwaggerVersion is version of Swagger documents converted to OpenAPI documents
+const SwaggerVersion = "2.0"
+
+// convertedVersion is OpenAPI version of document converted from Swagger document
+const convertedVersion = "3.0.3"
+
+const (
+	definitionsRefPrefix = "#/definitions/"
+	parametersRefPrefix  = "#/parameters/"
+	responsesRefPrefix   = "#/responses/"
+)
+
+// Media types of request body of formData parameters
+const (
+	formMediaType      = "application/x-www-form-urlencoded"
+	multipartMediaType = "multipart/form-data"
+)
+
+// Swagger is the root document object of the Swagger 2.0 document
+type Swagger struct {
+	Swagger string `json:"swagger" yaml:"swagger"`
+	Info    Info   `json:"info" yaml:"info"`
+	// BasePath prefixes paths of operations
+	BasePath    string       `json:"basePath,omitempty" yaml:"basePath,omitempty"`
+	Consumes    []string     `json:"consumes,omitempty" yaml:"consumes,omitempty"`
+	Produces    []string     `json:"produces,omitempty" yaml:"produces,omitempty"`
+	Paths       SwaggerPaths `json:"paths" yaml:"paths"`
+	Definitions Schemas      `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	// Parameters and Responses are shared by operations referencing them
+	Parameters map[string]SwaggerParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses  SwaggerResponses            `json:"responses,omitempty" yaml:"responses,omitempty"`
+
+	SecurityDefinitions SecuritySchemes      `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
+	Security            SecurityRequirements `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+// SwaggerPaths -.
+type SwaggerPaths map[string]*SwaggerPath
+
+// SwaggerPath -.
+type SwaggerPath struct {
+	Get        *SwaggerOperation `json:"get,omitempty" yaml:"get,omitempty"`
+	Put        *SwaggerOperation `json:"put,omitempty" yaml:"put,omitempty"`
+	Post       *SwaggerOperation `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete     *SwaggerOperation `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Options    *SwaggerOperation `json:"options,omitempty" yaml:"options,omitempty"`
+	Head       *SwaggerOperation `json:"head,omitempty" yaml:"head,omitempty"`
+	Patch      *SwaggerOperation `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Parameters SwaggerParameters `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// SwaggerOperation -.
+type SwaggerOperation struct {
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string            `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	OperationID string            `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Consumes    []string          `json:"consumes,omitempty" yaml:"consumes,omitempty"`
+	Produces    []string          `json:"produces,omitempty" yaml:"produces,omitempty"`
+	Parameters  SwaggerParameters `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses   SwaggerResponses  `json:"responses" yaml:"responses"`
+	// Security overrides default security requirements when not nil
+	Security *SecurityRequirements `json:"security,omitempty" yaml:"security,omitempty"`
+	// CORS overrides global CORS configuration for operation
+	CORS *CORS `json:"x-dummy-cors,omitempty" yaml:"x-dummy-cors,omitempty"`
+	// RateLimit overrides global rate limit for operation
+	RateLimit *RateLimit `json:"x-rate-limit,omitempty" yaml:"x-rate-limit,omitempty"`
+}
+
+// SwaggerParameters -.
+type SwaggerParameters []SwaggerParameter
+
+// SwaggerParameter -.
+type SwaggerParameter struct {
+	// Ref references shared parameter, e.g. `#/parameters/limit`
+	Ref         string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	In          string `json:"in" yaml:"in"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	// Schema of body parameter
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	// Type, Format, Items and Enum of other parameters
+	Type   string        `json:"type,omitempty" yaml:"type,omitempty"`
+	Format string        `json:"format,omitempty" yaml:"format,omitempty"`
+	Items  *Schema       `json:"items,omitempty" yaml:"items,omitempty"`
+	Enum   []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+}
+
+// schema returns schema of non-body parameter
+func (p SwaggerParameter) schema() *Schema {
+	if p.Type == "" {
+		return nil
+	}
+
+	// file of formData is sent as binary string
+	if p.Type == "file" {
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	return &Schema{
+		Type:   p.Type,
+		Format: p.Format,
+		Items:  p.Items,
+		Enum:   p.Enum,
+	}
+}
+
+// SwaggerResponses -.
+type SwaggerResponses map[string]*SwaggerResponse
+
+// SwaggerResponse -.
+type SwaggerResponse struct {
+	// Ref references shared response, e.g. `#/responses/NotFound`
+	Ref         string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string                    `json:"description" yaml:"description"`
+	Schema      *Schema                   `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Headers     map[string]*SwaggerHeader `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Examples of response by media type
+	Examples map[string]interface{} `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// SwaggerHeader -.
+type SwaggerHeader struct {
+	Description string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string        `json:"type" yaml:"type"`
+	Format      string        `json:"format,omitempty" yaml:"format,omitempty"`
+	Items       *Schema       `json:"items,omitempty" yaml:"items,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default     interface{}   `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// OpenAPI returns OpenAPI document equivalent to Swagger document.
+// Definitions become component schemas, body and formData parameters become request bodies,
+// response schemas become content of media types produced by operation and base path becomes server URL.
+func (s Swagger) OpenAPI() OpenAPI {
+	openapi := OpenAPI{
+		OpenAPI:  convertedVersion,
+		Info:     s.Info,
+		Paths:    make(Paths, len(s.Paths)),
+		Security: s.Security,
+	}
+
+	if s.BasePath != "" {
+		openapi.Servers = Servers{{URL: s.BasePath}}
+	}
+
+	if len(s.SecurityDefinitions) > 0 {
+		openapi.Components.SecuritySchemes = make(SecuritySchemes, len(s.SecurityDefinitions))
+
+		for name, scheme := range s.SecurityDefinitions {
+			openapi.Components.SecuritySchemes[name] = securityScheme(scheme)
+		}
+	}
+
+	if len(s.Definitions) > 0 {
+		openapi.Components.Schemas = make(Schemas, len(s.Definitions))
+
+		for name, schema := range s.Definitions {
+			openapi.Components.Schemas[name] = convertSchema(schema)
+		}
+	}
+
+	for path, p := range s.Paths {
+		if nil == p {
+			continue
+		}
+
+		openapi.Paths[path] = &Path{
+			Get:     s.operation(p.Get),
+			Put:     s.operation(p.Put),
+			Post:    s.operation(p.Post),
+			Delete:  s.operation(p.Delete),
+			Options: s.operation(p.Options),
+			Head:    s.operation(p.Head),
+			Patch:   s.operation(p.Patch),
+		}
+
+		openapi.Paths[path].Parameters, _ = s.parameters(p.Parameters, nil)
+	}
+
+	return openapi
+}
+
+func (s Swagger) operation(o *SwaggerOperation) *Operation {
+	if nil == o {
+		return nil
+	}
+
+	operation := &Operation{
+		Tags:        o.Tags,
+		Summary:     o.Summary,
+		Description: o.Description,
+		OperationID: o.OperationID,
+		Security:    o.Security,
+		CORS:        o.CORS,
+		RateLimit:   o.RateLimit,
+	}
+
+	consumes := mediaTypes(o.Consumes, s.Consumes)
+
+	operation.Parameters, operation.RequestBody = s.parameters(o.Parameters, consumes)
+
+	if len(o.Responses) > 0 {
+		operation.Responses = make(Responses, len(o.Responses))
+	}
+
+	produces := mediaTypes(o.Produces, s.Produces)
+
+	for code, r := range o.Responses {
+		if nil == r {
+			operation.Responses[code] = nil
+
+			continue
+		}
+
+		if strings.HasPrefix(r.Ref, responsesRefPrefix) {
+			if shared, ok := s.Responses[strings.TrimPrefix(r.Ref, responsesRefPrefix)]; ok && shared != nil {
+				r = shared
+			}
+		}
+
+		operation.Responses[code] = response(r, produces)
+	}
+
+	return operation
+}
+
+// parameters returns non-body parameters and request body of body or formData parameters with media types
+func (s Swagger) parameters(params SwaggerParameters, consumes []string) (Parameters, RequestBody) {
+	var (
+		res  Parameters
+		body RequestBody
+		form *Schema
+	)
+
+	for _, p := range params {
+		if strings.HasPrefix(p.Ref, parametersRefPrefix) {
+			if shared, ok := s.Parameters[strings.TrimPrefix(p.Ref, parametersRefPrefix)]; ok {
+				p = shared
+			}
+		}
+
+		switch p.In {
+		case "body":
+			body.Description = p.Description
+			body.Required = p.Required
+			body.Content = make(Content, len(consumes))
+
+			for _, mediaType := range consumes {
+				body.Content[mediaType] = &MediaType{Schema: schemaValue(p.Schema)}
+			}
+		case "formData":
+			// formData parameters are properties of single object
+			if nil == form {
+				form = &Schema{Type: "object", Properties: make(Schemas)}
+			}
+
+			form.Properties[p.Name] = p.schema()
+			if p.Required {
+				form.Required = append(form.Required, p.Name)
+				body.Required = true
+			}
+
+			if p.Type == "file" {
+				consumes = []string{multipartMediaType}
+			}
+		default:
+			res = append(res, Parameter{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.Required,
+				Schema:      p.schema(),
+			})
+		}
+	}
+
+	if form != nil {
+		body.Content = make(Content, len(consumes))
+
+		for _, mediaType := range formMediaTypes(consumes) {
+			body.Content[mediaType] = &MediaType{Schema: *form}
+		}
+	}
+
+	return res, body
+}
+
+// formMediaTypes returns form media types of consumed ones, URL-encoded form is default
+func formMediaTypes(consumes []string) []string {
+	var res []string
+
+	for _, mediaType := range consumes {
+		if mediaType == formMediaType || mediaType == multipartMediaType {
+			res = append(res, mediaType)
+		}
+	}
+
+	if len(res) == 0 {
+		return []string{formMediaType}
+	}
+
+	return res
+}
+
+func response(r *SwaggerResponse, produces []string) *Response {
+	res := &Response{
+		Description: r.Description,
+	}
+
+	if len(r.Headers) > 0 {
+		res.Headers = make(Headers, len(r.Headers))
+
+		for name, h := range r.Headers {
+			if nil == h {
+				continue
+			}
+
+			res.Headers[name] = &Header{
+				Description: h.Description,
+				Schema: &Schema{
+					Type:    h.Type,
+					Format:  h.Format,
+					Items:   h.Items,
+					Enum:    h.Enum,
+					Default: h.Default,
+				},
+			}
+		}
+	}
+
+	if nil == r.Schema && len(r.Examples) == 0 {
+		return res
+	}
+
+	res.Content = make(Content, len(produces))
+
+	for _, mediaType := range produces {
+		res.Content[mediaType] = &MediaType{
+			Schema:  schemaValue(r.Schema),
+			Example: r.Examples[mediaType],
+		}
+	}
+
+	return res
+}
+
+// securityScheme returns OpenAPI security scheme of Swagger one, basic type is HTTP basic scheme
+func securityScheme(s *SecurityScheme) *SecurityScheme {
+	if nil == s {
+		return nil
+	}
+
+	res := *s
+
+	if res.Type == "basic" {
+		res.Type, res.Scheme = "http", "basic"
+	}
+
+	return &res
+}
+
+// mediaTypes returns media types of operation, media types of document or JSON media type
+func mediaTypes(operation, document []string) []string {
+	if len(operation) > 0 {
+		return operation
+	}
+
+	if len(document) > 0 {
+		return document
+	}
+
+	return []string{"application/json"}
+}
+
+func schemaValue(s *Schema) Schema {
+	if nil == s {
+		return Schema{}
+	}
+
+	return *convertSchema(s)
+}
+
+// convertSchema rewrites references of definitions to references of component schemas
+func convertSchema(s *Schema) *Schema {
+	if nil == s {
+		return nil
+	}
+
+	if i := strings.Index(s.Ref, definitionsRefPrefix); i >= 0 {
+		s.Ref = s.Ref[:i] + schemasRefPrefix + s.Ref[i+len(definitionsRefPrefix):]
+	}
+
+	s.Items = convertSchema(s.Items)
+
+	if s.AdditionalProperties != nil {
+		s.AdditionalProperties.Schema = convertSchema(s.AdditionalProperties.Schema)
+	}
+
+	for name, p := range s.Properties {
+		s.Properties[name] = convertSchema(p)
+	}
+
+	for _, schemas := range [][]*Schema{s.AllOf, s.OneOf, s.AnyOf, s.PrefixItems} {
+		for i := range schemas {
+			schemas[i] = convertSchema(schemas[i])
+		}
+	}
+
+	return s
+}
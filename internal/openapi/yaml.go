@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxExpandedNodes limits count of nodes of YAML content with expanded aliases, e.g. of nested aliases
+// doubling content at each level
+const maxExpandedNodes = 1_000_000
+
+// ErrAliasExpansion -.
+var ErrAliasExpansion = errors.New("too many nodes of expanded YAML aliases")
+
+// mergeTag is tag of merge key `<<`
+const mergeTag = "!!merge"
+
+// ExpandAliases returns YAML content with aliases of anchors, e.g. `*base`, replaced by anchored nodes and merge keys,
+// e.g. `<<: *base`, replaced by keys of merged mappings. Keys of mapping take precedence over merged ones and
+// earlier merged mappings take precedence over later ones. Content without aliases or not decodable content
+// is returned as is, so errors are reported by decoder of content.
+func ExpandAliases(data []byte) ([]byte, error) {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil || !hasAlias(&doc) {
+		return data, nil
+	}
+
+	e := &expander{}
+
+	expanded := e.expand(&doc)
+	if e.nodes > maxExpandedNodes {
+		return nil, ErrAliasExpansion
+	}
+
+	return yaml.Marshal(expanded)
+}
+
+// hasAlias reports whether node or its descendants are aliases
+func hasAlias(n *yaml.Node) bool {
+	if n.Kind == yaml.AliasNode {
+		return true
+	}
+
+	for _, c := range n.Content {
+		if hasAlias(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expander counts nodes of expanded content
+type expander struct {
+	nodes int
+}
+
+// expand returns copy of node without anchors, aliases and merge keys
+func (e *expander) expand(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.AliasNode {
+		return e.expand(n.Alias)
+	}
+
+	e.nodes++
+	if e.nodes > maxExpandedNodes {
+		return n
+	}
+
+	res := *n
+	res.Anchor = ""
+	res.Content = nil
+
+	if n.Kind != yaml.MappingNode {
+		for _, c := range n.Content {
+			res.Content = append(res.Content, e.expand(c))
+		}
+
+		return &res
+	}
+
+	var merged []*yaml.Node
+
+	keys := make(map[string]struct{}, len(n.Content)/2)
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+
+		if key.ShortTag() == mergeTag {
+			merged = append(merged, mergedPairs(e.expand(value))...)
+
+			continue
+		}
+
+		keys[key.Value] = struct{}{}
+
+		res.Content = append(res.Content, e.expand(key), e.expand(value))
+	}
+
+	for i := 0; i+1 < len(merged); i += 2 {
+		if _, ok := keys[merged[i].Value]; ok {
+			continue
+		}
+
+		keys[merged[i].Value] = struct{}{}
+
+		res.Content = append(res.Content, merged[i], merged[i+1])
+	}
+
+	return &res
+}
+
+// mergedPairs returns keys and values of merged mapping or of sequence of merged mappings, in order of precedence
+func mergedPairs(value *yaml.Node) []*yaml.Node {
+	if value.Kind != yaml.SequenceNode {
+		return value.Content
+	}
+
+	var res []*yaml.Node
+
+	for _, m := range value.Content {
+		res = append(res, m.Content...)
+	}
+
+	return res
+}
@@ -2,13 +2,18 @@ package parse
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/goccy/go-yaml"
 	"github.com/neotoolkit/faker"
-	"github.com/neotoolkit/openapi"
 
 	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/openapi"
 	"github.com/neotoolkit/dummy/internal/read"
 )
 
@@ -20,7 +25,10 @@ const (
 	Unknown SpecType = "Unknown"
 )
 
-var ErrEmptySpecTypePath = errors.New("empty spec type path")
+var (
+	ErrEmptySpecTypePath = errors.New("empty spec type path")
+	ErrNotOpenAPI        = errors.New("specification is not OpenAPI document")
+)
 
 // SpecTypeError -.
 type SpecTypeError struct {
@@ -42,65 +50,259 @@ func (e *SpecFileError) Error() string {
 	return e.Path + " without format"
 }
 
+// Option configures specification parsing
+type Option func(*options)
+
+type options struct {
+	reader    read.Reader
+	seed      int64
+	basePaths []string
+	strict    bool
+	clock     api.Clock
+	// path of specification file, external references are resolved in fsys relative to base
+	path string
+	fsys fs.FS
+	base string
+}
+
+// WithReader sets reader of specification, e.g. with restricted URL allowlist
+func WithReader(r read.Reader) Option {
+	return func(o *options) {
+		o.reader = r
+	}
+}
+
+// WithSeed sets seed of generated examples, so same specification always yields same values, except UUIDs of faker.
+// Generated values are random when seed is not set.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithClock sets source of current time of generated dates and of rate limiting, e.g. frozen time in tests.
+// System clock is used when clock is not set.
+func WithClock(clock api.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithBasePath prefixes paths of operations with path instead of paths of servers URLs of specification,
+// option may be repeated for several prefixes
+func WithBasePath(path string) Option {
+	return func(o *options) {
+		o.basePaths = append(o.basePaths, path)
+	}
+}
+
+// WithStrict enables check of response examples against their schemas, mismatches fail parsing
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// withSource sets location of specification content, see parseFile
+func withSource(path string, fsys fs.FS, base string) Option {
+	return func(o *options) {
+		o.path = path
+		o.fsys = fsys
+		o.base = base
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		reader: read.Reader{Allowlist: read.DefaultAllowlist()},
+		seed:   time.Now().UnixNano(),
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
 // Parse -.
 func Parse(path string) (api.API, error) {
-	file, err := read.Read(path)
+	return ParseWithOptions(path)
+}
+
+// ParseWithOptions parses specification configured by opts
+func ParseWithOptions(path string, opts ...Option) (api.API, error) {
+	o := newOptions(opts)
+
+	file, err := o.reader.Read(path)
 	if err != nil {
 		return api.API{}, err
 	}
 
-	specType, err := GetSpecType(path)
-	if err != nil {
-		return api.API{}, err
+	// relative references of remote specification are resolved against its URL
+	if read.IsURL(path) {
+		return parseFile(path, file, nil, path, opts)
 	}
 
-	switch specType {
-	case OpenAPI:
-		oapi, err := openapi.Parse(file)
+	return parseFile(path, file, os.DirFS(filepath.Dir(path)), filepath.Base(path), opts)
+}
+
+// ParseAll parses specifications of paths, e.g. one per service, into single API, see ParseAllWithOptions
+func ParseAll(paths ...string) (api.API, error) {
+	return ParseAllWithOptions(paths)
+}
+
+// ParseAllWithOptions parses specifications of paths configured by opts into single API.
+// Each specification resolves its external references relative to itself, so specifications may share
+// components file. Operations matching same requests in several specifications are conflicting.
+func ParseAllWithOptions(paths []string, opts ...Option) (api.API, error) {
+	var res api.API
+
+	for _, path := range paths {
+		a, err := ParseWithOptions(path, opts...)
 		if err != nil {
-			return api.API{}, err
+			return api.API{}, fmt.Errorf("%s: %w", path, err)
 		}
 
-		f := faker.NewFaker()
-
-		b := &api.Builder{
-			OpenAPI: oapi,
-			Faker:   f,
+		res, err = res.Merge(a)
+		if err != nil {
+			return api.API{}, fmt.Errorf("%s: %w", path, err)
 		}
+	}
+
+	return res, nil
+}
+
+// ParseFS parses specification from path of fsys, e.g. embed.FS.
+// External references, e.g. `schemas.yml#/components/schemas/User`, are resolved relative to specification.
+func ParseFS(fsys fs.FS, path string, opts ...Option) (api.API, error) {
+	file, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return api.API{}, err
+	}
+
+	return parseFile(path, file, fsys, path, opts)
+}
+
+// ParseBytes parses OpenAPI or Swagger specification from content, e.g. generated in memory.
+// Only local and URL references are resolved, since content has no location for relative ones.
+func ParseBytes(file []byte, opts ...Option) (api.API, error) {
+	o := newOptions(opts)
 
-		return b.Build()
-	case GraphQL:
+	oapi, err := parseOpenAPI(o.path, file)
+	if err != nil {
+		return api.API{}, err
+	}
+
+	if len(oapi.OpenAPI) == 0 {
+		return api.API{}, ErrNotOpenAPI
+	}
+
+	return build(oapi, o)
+}
+
+// ParseReader parses OpenAPI or Swagger specification read from r, see ParseBytes
+func ParseReader(r io.Reader, opts ...Option) (api.API, error) {
+	file, err := io.ReadAll(r)
+	if err != nil {
+		return api.API{}, err
+	}
+
+	return ParseBytes(file, opts...)
+}
+
+// parseFile parses content of specification file of path by ParseBytes, external references are resolved
+// in fsys relative to base. GraphQL schema yields empty API.
+func parseFile(path string, file []byte, fsys fs.FS, base string, opts []Option) (api.API, error) {
+	ext, err := extension(path)
+	if err != nil {
+		return api.API{}, err
+	}
+
+	switch ext {
+	case "yml", "yaml", "json":
+	case "graphql":
 		return api.API{}, nil
+	default:
+		return api.API{}, &SpecTypeError{
+			Path: path,
+		}
+	}
+
+	// source is appended to copy of options, so slice of caller is not modified
+	a, err := ParseBytes(file, append(opts[:len(opts):len(opts)], withSource(path, fsys, base))...)
+	if errors.Is(err, ErrNotOpenAPI) {
+		return api.API{}, &SpecTypeError{
+			Path: path,
+		}
+	}
+
+	return a, err
+}
+
+// build returns API of OpenAPI document, external references are resolved in options fsys relative to base
+func build(oapi openapi.OpenAPI, o options) (api.API, error) {
+	rnd := api.NewRand(o.seed)
+
+	// faker shares generator of examples, so `x-faker` values are reproducible by seed too
+	f := faker.NewFaker()
+	f.Generator = rnd
+
+	b := &api.Builder{
+		OpenAPI:   oapi,
+		Faker:     f,
+		Rand:      rnd,
+		Clock:     o.clock,
+		FS:        o.fsys,
+		Base:      o.base,
+		Fetch:     o.reader.Read,
+		BasePaths: o.basePaths,
+		Strict:    o.strict,
 	}
 
-	return api.API{}, nil
+	return b.Build()
 }
 
 // GetSpecType returns specification type for path
 func GetSpecType(path string) (SpecType, error) {
+	if _, err := extension(path); err != nil {
+		return Unknown, err
+	}
+
+	file, err := read.Read(path)
+	if err != nil {
+		return Unknown, err
+	}
+
+	return specType(path, file)
+}
+
+func extension(path string) (string, error) {
 	if len(path) == 0 {
-		return Unknown, ErrEmptySpecTypePath
+		return "", ErrEmptySpecTypePath
 	}
 
 	splitPath := strings.Split(path[1:], ".")
 
 	if len(splitPath) == 1 {
-		return Unknown, &SpecFileError{
+		return "", &SpecFileError{
 			Path: path,
 		}
 	}
 
-	file, err := read.Read(path)
+	return splitPath[len(splitPath)-1], nil
+}
+
+func specType(path string, file []byte) (SpecType, error) {
+	ext, err := extension(path)
 	if err != nil {
 		return Unknown, err
 	}
 
-	switch splitPath[len(splitPath)-1] {
-	case "yml", "yaml":
-		var openapi openapi.OpenAPI
-
-		err := yaml.Unmarshal(file, &openapi)
-		if err != nil || len(openapi.OpenAPI) == 0 {
+	switch ext {
+	case "yml", "yaml", "json":
+		oapi, err := parseOpenAPI(path, file)
+		if err != nil || len(oapi.OpenAPI) == 0 {
 			return Unknown, &SpecTypeError{
 				Path: path,
 			}
@@ -115,3 +317,12 @@ func GetSpecType(path string) (SpecType, error) {
 		}
 	}
 }
+
+// parseOpenAPI decodes specification by JSON decoder for `.json` files and by sniffed format otherwise
+func parseOpenAPI(path string, file []byte) (openapi.OpenAPI, error) {
+	if strings.HasSuffix(path, ".json") {
+		return openapi.ParseJSON(file)
+	}
+
+	return openapi.Parse(file)
+}
@@ -1,15 +1,24 @@
 package parse_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"sort"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/neotoolkit/dummy/internal/api"
 	"github.com/neotoolkit/dummy/internal/parse"
+	"github.com/neotoolkit/dummy/internal/read"
 )
 
 func TestSpecTypeError(t *testing.T) {
@@ -104,6 +113,7 @@ func TestParse_YAML(t *testing.T) {
 					"id": {
 						Required: true,
 						Type:     "string",
+						Format:   "uuid",
 					},
 					"firstName": {
 						Required: true,
@@ -146,7 +156,23 @@ func TestParse_YAML(t *testing.T) {
 								},
 								Example: map[string]interface{}{},
 							},
-							Example: []interface{}{},
+							Example: []interface{}{
+								map[string]interface{}{
+									"id":        "380ed0b7-eb21-4ad4-acd0-efa90cf69c6a",
+									"firstName": "Larry",
+									"lastName":  "Page",
+								},
+								map[string]interface{}{
+									"id":        "380ed0b7-eb21-4ad4-acd0-efa90cf69c6a",
+									"firstName": "Larry",
+									"lastName":  "Page",
+								},
+								map[string]interface{}{
+									"id":        "380ed0b7-eb21-4ad4-acd0-efa90cf69c6a",
+									"firstName": "Larry",
+									"lastName":  "Page",
+								},
+							},
 						},
 						Example: []map[string]interface{}{
 							{
@@ -192,24 +218,443 @@ func TestParse_YAML(t *testing.T) {
 	require.Equalf(t, testable(t, expected), testable(t, openapi), `parsed schema from "testdata/openapi3.yml"`)
 }
 
-func testable(t *testing.T, api api.API) api.API {
+func TestParse_JSON(t *testing.T) {
+	yml, err := parse.Parse("testdata/openapi3.yml")
+	require.NoError(t, err)
+
+	json, err := parse.Parse("testdata/openapi3.json")
+	require.NoError(t, err)
+
+	require.Equal(t, testable(t, yml), testable(t, json))
+}
+
+func TestParse_Anchors(t *testing.T) {
+	anchors, err := parse.ParseWithOptions("testdata/anchors.yml", parse.WithSeed(42))
+	require.NoError(t, err)
+
+	expanded, err := parse.ParseWithOptions("testdata/anchors-expanded.yml", parse.WithSeed(42))
+	require.NoError(t, err)
+
+	require.Equal(t, testable(t, expanded), testable(t, anchors))
+}
+
+func TestParse_Swagger(t *testing.T) {
+	openapi, err := parse.Parse("testdata/openapi3.yml")
+	require.NoError(t, err)
+
+	swagger, err := parse.Parse("testdata/swagger.yml")
+	require.NoError(t, err)
+
+	require.Equal(t, testable(t, openapi), testable(t, swagger))
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, path := range []string{"testdata/openapi3.yml", "testdata/openapi3.json", "testdata/swagger.yml", "testdata/generated.yml"} {
+		t.Run(path, func(t *testing.T) {
+			want, err := parse.ParseWithOptions(path, parse.WithSeed(42))
+			require.NoError(t, err)
+
+			file, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			got, err := parse.ParseBytes(file, parse.WithSeed(42))
+			require.NoError(t, err)
+			require.Equal(t, testable(t, want), testable(t, got))
+
+			got, err = parse.ParseReader(bytes.NewReader(file), parse.WithSeed(42))
+			require.NoError(t, err)
+			require.Equal(t, testable(t, want), testable(t, got))
+		})
+	}
+
+	_, err := parse.ParseBytes([]byte("title: not OpenAPI"))
+	require.ErrorIs(t, err, parse.ErrNotOpenAPI)
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.yml": {
+			Data: []byte(`openapi: 3.0.3
+info:
+  title: Users dummy API
+  version: 0.1.0
+paths:
+  /users/{userId}:
+    get:
+      responses:
+        '200':
+          description: ''
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/user.yml#/components/schemas/User'
+  /me:
+    get:
+      responses:
+        '200':
+          description: ''
+          content:
+            application/json:
+              schema:
+                $ref: './schemas/me.yml'
+`),
+		},
+		"schemas/user.yml": {
+			Data: []byte(`openapi: 3.0.3
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        firstName:
+          type: string
+          example: Larry
+`),
+		},
+		"schemas/me.yml": {
+			Data: []byte(`type: object
+properties:
+  lastName:
+    type: string
+    example: Page
+`),
+		},
+	}
+
+	got, err := parse.ParseFS(fsys, "openapi.yml")
+	require.NoError(t, err)
+
+	got = testable(t, got)
+	require.Len(t, got.Operations, 2)
+
+	require.Equal(t, "/me", got.Operations[0].Path)
+	require.Equal(t, map[string]interface{}{"lastName": "Page"}, got.Operations[0].Responses[0].ExampleValue(""))
+
+	require.Equal(t, "/users/{userId}", got.Operations[1].Path)
+	require.Equal(t, map[string]interface{}{"firstName": "Larry"}, got.Operations[1].Responses[0].ExampleValue(""))
+
+	_, err = parse.ParseFS(fsys, "unknown.yml")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestParse_ExternalReferences(t *testing.T) {
+	got, err := parse.Parse("./testdata/external/openapi.yml")
+	require.NoError(t, err)
+
+	got = testable(t, got)
+	require.Len(t, got.Operations, 2)
+
+	user := map[string]interface{}{
+		"firstName": "Larry",
+		"address": map[string]interface{}{
+			"city":    "Palo Alto",
+			"country": "United States",
+		},
+	}
+
+	require.Equal(t, "/orgs/{orgId}", got.Operations[0].Path)
+	require.Equal(t, map[string]interface{}{
+		"title": "Google",
+		"owner": user,
+	}, got.Operations[0].Responses[0].ExampleValue(""))
+
+	require.Equal(t, "/users/{userId}", got.Operations[1].Path)
+	require.Equal(t, user, got.Operations[1].Responses[0].ExampleValue(""))
+}
+
+func TestParse_ExternalValue(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"firstName": "Sergey"}`)
+	}))
+	defer ts.Close()
+
+	spec := func(externalValue string) []byte {
+		return []byte(`openapi: 3.0.3
+info:
+  title: Users dummy API
+  version: 0.1.0
+paths:
+  /users/{userId}:
+    get:
+      responses:
+        '200':
+          description: ''
+          content:
+            application/json:
+              schema:
+                type: object
+              examples:
+                larry:
+                  value:
+                    firstName: Larry
+                external:
+                  externalValue: ` + externalValue + `
+`)
+	}
+
+	tests := []struct {
+		name          string
+		externalValue string
+		allowlist     read.Allowlist
+		want          interface{}
+		err           error
+	}{
+		{
+			name:          "relative path",
+			externalValue: "examples/user.json",
+			allowlist:     read.DefaultAllowlist(),
+			want:          map[string]interface{}{"firstName": "Elon"},
+		},
+		{
+			name:          "allowed host",
+			externalValue: ts.URL + "/user.json",
+			allowlist:     read.Allowlist{Schemes: []string{"http"}, Hosts: []string{"127.0.0.1"}},
+			want:          map[string]interface{}{"firstName": "Sergey"},
+		},
+		{
+			name:          "blocked host",
+			externalValue: ts.URL + "/user.json",
+			allowlist:     read.Allowlist{Schemes: []string{"http"}, Hosts: []string{"example.com"}},
+			err:           &read.URLNotAllowedError{URL: ts.URL + "/user.json"},
+		},
+		{
+			name:          "blocked scheme",
+			externalValue: "file:///etc/passwd",
+			allowlist:     read.DefaultAllowlist(),
+			err:           &read.URLNotAllowedError{URL: "file:///etc/passwd"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"openapi.yml":        {Data: spec(tc.externalValue)},
+				"examples/user.json": {Data: []byte(`{"firstName": "Elon"}`)},
+			}
+
+			got, err := parse.ParseFS(fsys, "openapi.yml", parse.WithReader(read.Reader{Allowlist: tc.allowlist}))
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, map[string]interface{}{"firstName": "Larry"},
+				got.Operations[0].Responses[0].ExampleValue("larry"))
+			require.Equal(t, tc.want, got.Operations[0].Responses[0].ExampleValue("external"))
+		})
+	}
+}
+
+func TestParse_OpenAPI31(t *testing.T) {
+	got, err := parse.Parse("./testdata/openapi31.yml")
+	require.NoError(t, err)
+
+	require.Len(t, got.Operations, 1)
+	require.Equal(t, map[string]interface{}{
+		"kind":      "user",
+		"name":      "Elon Musk",
+		"age":       int64(42),
+		"location":  []interface{}{37.4, -122.1},
+		"deletedAt": nil,
+	}, got.Operations[0].Responses[0].ExampleValue(""))
+}
+
+func TestParseAll(t *testing.T) {
+	got, err := parse.ParseAll("./testdata/merge/users.yml", "./testdata/merge/orgs.yml")
+	require.NoError(t, err)
+
+	user := map[string]interface{}{"name": "Larry"}
+
+	for _, path := range []string{"/users", "/users/1", "/orgs/1"} {
+		_, ok := got.FindOperation(http.MethodGet, path)
+		require.True(t, ok, path)
+	}
+
+	got = testable(t, got)
+	require.Len(t, got.Operations, 3)
+
+	require.Equal(t, "/orgs/{orgId}", got.Operations[0].Path)
+	require.Equal(t, map[string]interface{}{
+		"title": "Google",
+		"owner": user,
+	}, got.Operations[0].Responses[0].ExampleValue(""))
+
+	require.Equal(t, "/users", got.Operations[1].Path)
+	users, ok := got.Operations[1].Responses[0].ExampleValue("").([]interface{})
+	require.True(t, ok)
+	require.Equal(t, user, users[0])
+
+	require.Equal(t, "/users/{userId}", got.Operations[2].Path)
+	require.Equal(t, user, got.Operations[2].Responses[0].ExampleValue(""))
+}
+
+func TestParseAll_Conflict(t *testing.T) {
+	_, err := parse.ParseAll("./testdata/merge/users.yml", "./testdata/merge/conflict.yml")
+
+	var conflictError *api.OperationConflictError
+
+	require.ErrorAs(t, err, &conflictError)
+	require.Equal(t, &api.OperationConflictError{Method: http.MethodGet, Path: "/users"}, conflictError)
+	require.EqualError(t, err, "./testdata/merge/conflict.yml: conflicting operation GET /users")
+}
+
+func TestParse_URLReferences(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/specs/openapi.yml", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "./testdata/external/openapi.yml")
+	})
+	mux.Handle("/specs/schemas/", http.StripPrefix("/specs/", http.FileServer(http.Dir("./testdata/external"))))
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	got, err := parse.Parse(ts.URL + "/specs/openapi.yml")
+	require.NoError(t, err)
+
+	got = testable(t, got)
+	require.Len(t, got.Operations, 2)
+
+	require.Equal(t, "/users/{userId}", got.Operations[1].Path)
+	require.Equal(t, map[string]interface{}{
+		"firstName": "Larry",
+		"address": map[string]interface{}{
+			"city":    "Palo Alto",
+			"country": "United States",
+		},
+	}, got.Operations[1].Responses[0].ExampleValue(""))
+}
+
+func TestParse_CircularExternalReferences(t *testing.T) {
+	_, err := parse.Parse("./testdata/external/circular.yml")
+
+	var circularReferenceError *api.CircularReferenceError
+
+	require.ErrorAs(t, err, &circularReferenceError)
+	require.Equal(t, "/schemas/node.yml", circularReferenceError.Ref)
+}
+
+func TestParseWithOptions_Seed(t *testing.T) {
+	examples := func(t *testing.T, a api.API) []byte {
+		t.Helper()
+
+		a = testable(t, a)
+
+		values := make([]interface{}, 0, len(a.Operations))
+		for _, op := range a.Operations {
+			values = append(values, op.Responses[0].ExampleValue(""))
+		}
+
+		data, err := json.Marshal(values)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	first, err := parse.ParseWithOptions("testdata/generated.yml", parse.WithSeed(42))
+	require.NoError(t, err)
+
+	second, err := parse.ParseWithOptions("testdata/generated.yml", parse.WithSeed(42))
+	require.NoError(t, err)
+
+	other, err := parse.ParseWithOptions("testdata/generated.yml", parse.WithSeed(43))
+	require.NoError(t, err)
+
+	require.Equal(t, examples(t, first), examples(t, second))
+	require.NotEqual(t, examples(t, first), examples(t, other))
+
+	nickname := func(t *testing.T, a api.API) interface{} {
+		t.Helper()
+
+		op, ok := a.FindOperation(http.MethodGet, "/users/1")
+		require.True(t, ok)
+
+		user, ok := op.Responses[0].ExampleValue("").(map[string]interface{})
+		require.True(t, ok)
+		require.NotEmpty(t, user["nickname"])
+
+		return user["nickname"]
+	}
+
+	require.Equal(t, nickname(t, first), nickname(t, second))
+	require.NotEqual(t, nickname(t, first), nickname(t, other))
+}
+
+func TestParseWithOptions_Clock(t *testing.T) {
+	spec := []byte(`openapi: 3.0.3
+info:
+  title: Clock dummy API
+  version: 0.1.0
+paths:
+  /users/{userId}:
+    get:
+      responses:
+        '200':
+          description: ''
+          headers:
+            Last-Modified:
+              schema:
+                type: string
+                format: date-time
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  birthday:
+                    type: string
+                    format: date
+                  createdAt:
+                    type: string
+                    format: date-time
+`)
+
+	now := time.Date(2022, time.February, 24, 10, 30, 0, 0, time.UTC)
+	clock := api.ClockFunc(func() time.Time {
+		return now
+	})
+
+	got, err := parse.ParseBytes(spec, parse.WithClock(clock))
+	require.NoError(t, err)
+
+	response := got.Operations[0].Responses[0]
+
+	require.Equal(t, map[string]interface{}{
+		"birthday":  "2022-02-24",
+		"createdAt": "2022-02-24T10:30:00Z",
+	}, response.ExampleValue(""))
+	require.Equal(t, "2022-02-24T10:30:00Z", response.Headers[0].Value())
+	require.Equal(t, now, got.Clock.Now())
+}
+
+func testable(t *testing.T, a api.API) api.API {
 	t.Helper()
 
-	sort.Slice(api.Operations, func(i, j int) bool {
-		a, b := api.Operations[i], api.Operations[j]
+	sort.Slice(a.Operations, func(i, j int) bool {
+		x, y := a.Operations[i], a.Operations[j]
 
-		if a.Method > b.Method {
+		if x.Method > y.Method {
 			return false
 		}
 
-		if a.Method < b.Method {
+		if x.Method < y.Method {
 			return true
 		}
 
-		return a.Path < b.Path
+		return x.Path < y.Path
 	})
 
-	return api
+	// drop unexported lookup index, it is built from Operations
+	return api.API{
+		Operations:            a.Operations,
+		ValidateAnyMethodBody: a.ValidateAnyMethodBody,
+		DuplicateKeys:         a.DuplicateKeys,
+		Logger:                a.Logger,
+		Echo:                  a.Echo,
+		Store:                 a.Store,
+		Latency:               a.Latency,
+	}
 }
 
 func TestGetSpecType(t *testing.T) {
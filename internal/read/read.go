@@ -1,33 +1,202 @@
 package read
 
 import (
+	"errors"
 	"io/ioutil"
 	"net/http"
+	neturl "net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const (
+	// DefaultTimeout limits duration of remote specification request
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetries is count of repeated remote specification requests on transient errors
+	DefaultRetries = 2
+	// DefaultBackoff is delay before first repeated request, delay doubles for each next one
+	DefaultBackoff = 500 * time.Millisecond
+	// maxRedirects limits count of followed redirects of remote specification request
+	maxRedirects = 10
+)
+
+// ErrTooManyRedirects -.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// Allowlist restricts schemes and hosts of remote specifications. Any host is allowed when Hosts is empty.
+type Allowlist struct {
+	Schemes []string
+	Hosts   []string
+}
+
+// DefaultAllowlist returns allowlist permitting http and https URLs of any host
+func DefaultAllowlist() Allowlist {
+	return Allowlist{
+		Schemes: []string{"http", "https"},
+	}
+}
+
+// URLNotAllowedError -.
+type URLNotAllowedError struct {
+	URL string
+}
+
+// Error -.
+func (e *URLNotAllowedError) Error() string {
+	return "URL not allowed: " + e.URL
+}
+
+// Allow returns error if URL scheme or host is not in allowlist
+func (a Allowlist) Allow(u *neturl.URL) error {
+	if !contains(a.Schemes, u.Scheme) {
+		return &URLNotAllowedError{URL: u.String()}
+	}
+
+	if len(a.Hosts) > 0 && !contains(a.Hosts, u.Hostname()) {
+		return &URLNotAllowedError{URL: u.String()}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StatusError -.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+// Error -.
+func (e *StatusError) Error() string {
+	return "unexpected status code " + strconv.Itoa(e.StatusCode) + " of " + e.URL
+}
+
+// Reader reads specification from file or URL permitted by Allowlist.
+// Remote specification request is repeated on connection errors and 5xx responses.
+type Reader struct {
+	Allowlist Allowlist
+	// Timeout limits duration of each request, DefaultTimeout is used when zero
+	Timeout time.Duration
+	// Retries is count of repeated requests, DefaultRetries is used when zero, negative count disables retries
+	Retries int
+	// Backoff is delay before first repeated request, DefaultBackoff is used when zero
+	Backoff time.Duration
+}
+
 // Read -.
 func Read(path string) ([]byte, error) {
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
-		return url(path)
+	return Reader{Allowlist: DefaultAllowlist()}.Read(path)
+}
+
+// Read -.
+func (r Reader) Read(path string) ([]byte, error) {
+	if !IsURL(path) {
+		return file(path)
+	}
+
+	u, err := neturl.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Allowlist.Allow(u); err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "file" {
+		return file(u.Path)
+	}
+
+	return r.url(path)
+}
+
+// IsURL reports whether path is URL, otherwise it is file path
+func IsURL(path string) bool {
+	return strings.Contains(path, "://")
+}
+
+func (r Reader) url(url string) ([]byte, error) {
+	client := &http.Client{
+		Timeout:       r.Timeout,
+		CheckRedirect: r.checkRedirect,
+	}
+	if client.Timeout == 0 {
+		client.Timeout = DefaultTimeout
+	}
+
+	retries := r.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	}
+
+	backoff := r.Backoff
+	if backoff == 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, retry, err := get(client, url)
+		if err == nil || !retry || attempt >= retries {
+			return body, err
+		}
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+	}
+}
+
+// checkRedirect returns error if redirect URL is not in allowlist, so allowed host can not redirect
+// to blocked one, e.g. to metadata endpoint
+func (r Reader) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return ErrTooManyRedirects
 	}
 
-	return file(path)
+	return r.Allowlist.Allow(req.URL)
 }
 
-func url(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// get returns body of successful response, retry is reported for transient errors
+func get(client *http.Client, url string) (body []byte, retry bool, err error) {
+	resp, err := client.Get(url)
 	if err != nil {
-		return nil, err
+		// redirect errors are returned as is, they are not transient
+		var notAllowed *URLNotAllowedError
+		if errors.As(err, &notAllowed) {
+			return nil, false, notAllowed
+		}
+
+		if errors.Is(err, ErrTooManyRedirects) {
+			return nil, false, ErrTooManyRedirects
+		}
+
+		return nil, true, err
 	}
 	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, resp.StatusCode >= http.StatusInternalServerError, &StatusError{
+			URL:        url,
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	body, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, true, err
 	}
 
-	return body, nil
+	return body, false, nil
 }
 
 func file(path string) ([]byte, error) {
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -97,3 +99,227 @@ func TestRead(t *testing.T) {
 		})
 	}
 }
+
+func TestReader_Read(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `openapi: 3.0.3`)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		allowlist read.Allowlist
+		path      string
+		want      []byte
+		err       error
+	}{
+		{
+			name: "allowed host",
+			allowlist: read.Allowlist{
+				Schemes: []string{"http"},
+				Hosts:   []string{"127.0.0.1"},
+			},
+			path: ts.URL,
+			want: []byte(`openapi: 3.0.3`),
+		},
+		{
+			name: "blocked host",
+			allowlist: read.Allowlist{
+				Schemes: []string{"http"},
+				Hosts:   []string{"example.com"},
+			},
+			path: ts.URL,
+			err:  &read.URLNotAllowedError{URL: ts.URL},
+		},
+		{
+			name:      "blocked scheme",
+			allowlist: read.DefaultAllowlist(),
+			path:      "file:///etc/passwd",
+			err:       &read.URLNotAllowedError{URL: "file:///etc/passwd"},
+		},
+		{
+			name:      "metadata endpoint",
+			allowlist: read.Allowlist{Schemes: []string{"http"}, Hosts: []string{"127.0.0.1"}},
+			path:      "http://169.254.169.254/latest/meta-data/",
+			err:       &read.URLNotAllowedError{URL: "http://169.254.169.254/latest/meta-data/"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := read.Reader{Allowlist: tc.allowlist}.Read(tc.path)
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestReader_Read_Redirect(t *testing.T) {
+	const metadata = "http://169.254.169.254/latest/meta-data/"
+
+	var requests int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/allowed":
+			http.Redirect(w, r, "/openapi.yml", http.StatusFound)
+		case "/blocked":
+			http.Redirect(w, r, metadata, http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			fmt.Fprint(w, `openapi: 3.0.3`)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		want     []byte
+		err      error
+		requests int32
+	}{
+		{
+			name:     "allowed host",
+			path:     "/allowed",
+			want:     []byte(`openapi: 3.0.3`),
+			requests: 2,
+		},
+		{
+			name:     "blocked host",
+			path:     "/blocked",
+			err:      &read.URLNotAllowedError{URL: metadata},
+			requests: 1,
+		},
+		{
+			name:     "too many redirects",
+			path:     "/loop",
+			err:      read.ErrTooManyRedirects,
+			requests: 10,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+
+			r := read.Reader{
+				Allowlist: read.Allowlist{Schemes: []string{"http"}, Hosts: []string{"127.0.0.1"}},
+				Backoff:   time.Millisecond,
+			}
+
+			got, err := r.Read(ts.URL + tc.path)
+
+			require.Equal(t, tc.requests, atomic.LoadInt32(&requests))
+
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	got := &read.StatusError{
+		URL:        "test",
+		StatusCode: http.StatusNotFound,
+	}
+
+	require.Equal(t, got.Error(), "unexpected status code 404 of test")
+}
+
+func TestReader_Read_Remote(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		hang     bool
+		want     []byte
+		// statusCode of StatusError, no error is expected when zero
+		statusCode int
+		requests   int32
+	}{
+		{
+			name:     "hanging server",
+			hang:     true,
+			requests: 1,
+		},
+		{
+			name:     "service unavailable twice",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			want:     []byte(`openapi: 3.0.3`),
+			requests: 3,
+		},
+		{
+			name:       "service unavailable",
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			statusCode: http.StatusServiceUnavailable,
+			requests:   3,
+		},
+		{
+			name:       "not found",
+			statuses:   []int{http.StatusNotFound},
+			statusCode: http.StatusNotFound,
+			requests:   1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&requests, 1) - 1
+
+				if tc.hang {
+					<-r.Context().Done()
+
+					return
+				}
+
+				w.WriteHeader(tc.statuses[i])
+				fmt.Fprint(w, `openapi: 3.0.3`)
+			}))
+			defer ts.Close()
+
+			r := read.Reader{
+				Allowlist: read.DefaultAllowlist(),
+				Timeout:   50 * time.Millisecond,
+				Backoff:   time.Millisecond,
+			}
+
+			if tc.hang {
+				r.Retries = -1
+			}
+
+			got, err := r.Read(ts.URL)
+
+			require.Equal(t, tc.requests, atomic.LoadInt32(&requests))
+
+			switch {
+			case tc.hang:
+				var netErr interface{ Timeout() bool }
+				require.ErrorAs(t, err, &netErr)
+				require.True(t, netErr.Timeout())
+			case tc.statusCode != 0:
+				require.Equal(t, &read.StatusError{URL: ts.URL, StatusCode: tc.statusCode}, err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
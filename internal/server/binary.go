@@ -0,0 +1,29 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+// binary writes binary response body. Range requests are supported for 200 responses.
+func (s *Server) binary(w http.ResponseWriter, r *http.Request, response api.Response, resp interface{}) {
+	w.Header().Set("Content-Type", response.MediaType)
+
+	body := rawBody(resp)
+
+	if response.StatusCode == http.StatusOK {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+
+		return
+	}
+
+	w.WriteHeader(response.StatusCode)
+
+	_, err := w.Write(body)
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("write response")
+	}
+}
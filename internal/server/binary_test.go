@@ -0,0 +1,63 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Handler_Range(t *testing.T) {
+	s := newServer(t, "./testdata/binary.yml")
+
+	tests := []struct {
+		name         string
+		rangeHeader  string
+		statusCode   int
+		contentRange string
+		want         string
+	}{
+		{
+			name:         "without range",
+			rangeHeader:  "",
+			statusCode:   http.StatusOK,
+			contentRange: "",
+			want:         "0123456789",
+		},
+		{
+			name:         "valid range",
+			rangeHeader:  "bytes=2-5",
+			statusCode:   http.StatusPartialContent,
+			contentRange: "bytes 2-5/10",
+			want:         "2345",
+		},
+		{
+			name:         "out of bounds range",
+			rangeHeader:  "bytes=20-30",
+			statusCode:   http.StatusRequestedRangeNotSatisfiable,
+			contentRange: "bytes */10",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/file", nil)
+
+			if tc.rangeHeader != "" {
+				r.Header.Set("Range", tc.rangeHeader)
+			}
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.contentRange, w.Header().Get("Content-Range"))
+
+			if tc.want != "" {
+				require.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+				require.Equal(t, tc.want, w.Body.String())
+			}
+		})
+	}
+}
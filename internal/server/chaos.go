@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+// chaos injects failure of chaos configuration of API. Writer wrapped to truncate response body is returned
+// for FailureTruncate, true is returned when response is written or request is canceled meanwhile.
+func (s *Server) chaos(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, bool) {
+	chaos := s.Handlers.API.Chaos
+
+	switch chaos.Failure(s.Handlers.Rand) {
+	case api.FailureStatus:
+		w.WriteHeader(chaos.StatusCode(s.Handlers.Rand))
+
+		return w, true
+	case api.FailureTruncate:
+		return truncatedResponseWriter{w}, false
+	case api.FailureSlow:
+		return w, !sleep(r, chaos.SlowDelay())
+	default:
+		return w, false
+	}
+}
+
+// truncatedResponseWriter writes first half of response body, so body is malformed
+type truncatedResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write -.
+func (w truncatedResponseWriter) Write(b []byte) (int, error) {
+	if _, err := w.ResponseWriter.Write(b[:len(b)/2]); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
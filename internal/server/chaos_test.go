@@ -0,0 +1,73 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+func TestServer_Handler_Chaos(t *testing.T) {
+	const body = `{"firstName":"Elon"}`
+
+	tests := []struct {
+		name  string
+		chaos api.Chaos
+		check func(t *testing.T, w *httptest.ResponseRecorder, elapsed time.Duration)
+	}{
+		{
+			name:  "no failures",
+			chaos: api.Chaos{Rate: 0, Failures: []api.Failure{api.FailureStatus}},
+			check: func(t *testing.T, w *httptest.ResponseRecorder, _ time.Duration) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.JSONEq(t, body, w.Body.String())
+			},
+		},
+		{
+			name:  "status",
+			chaos: api.Chaos{Rate: 1, Failures: []api.Failure{api.FailureStatus}},
+			check: func(t *testing.T, w *httptest.ResponseRecorder, _ time.Duration) {
+				require.GreaterOrEqual(t, w.Code, http.StatusInternalServerError)
+				require.Empty(t, w.Body.String())
+			},
+		},
+		{
+			name:  "truncate",
+			chaos: api.Chaos{Rate: 1, Failures: []api.Failure{api.FailureTruncate}},
+			check: func(t *testing.T, w *httptest.ResponseRecorder, _ time.Duration) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, body[:len(body)/2], w.Body.String())
+			},
+		},
+		{
+			name:  "slow",
+			chaos: api.Chaos{Rate: 1, Failures: []api.Failure{api.FailureSlow}, Delay: 20 * time.Millisecond},
+			check: func(t *testing.T, w *httptest.ResponseRecorder, elapsed time.Duration) {
+				require.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+				require.Equal(t, http.StatusOK, w.Code)
+				require.JSONEq(t, body, w.Body.String())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, "./testdata/status-codes.yml")
+			s.Handlers.API.Chaos = tc.chaos
+
+			for i := 0; i < 10; i++ {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+
+				start := time.Now()
+				s.Handler(w, r)
+
+				tc.check(t, w, time.Since(start))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/neotoolkit/dummy/internal/config"
+)
+
+// cors writes CORS headers for cross-origin request and reports whether preflight request is handled.
+// Operation specific configuration is merged over global one.
+func (s *Server) cors(w http.ResponseWriter, r *http.Request, path string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+
+	requestMethod := r.Header.Get("Access-Control-Request-Method")
+	preflight := r.Method == http.MethodOptions && requestMethod != ""
+
+	method := r.Method
+	if preflight {
+		method = requestMethod
+	}
+
+	conf := s.Config.CORS
+
+	if operation, ok := s.Handlers.API.FindOperation(method, path); ok && operation.CORS != nil {
+		conf = mergeCORS(conf, config.CORS{
+			Origins: operation.CORS.Origins,
+			Methods: operation.CORS.Methods,
+			Headers: operation.CORS.Headers,
+		})
+	}
+
+	allowOrigin, ok := corsAllowOrigin(conf.Origins, origin)
+	if !ok {
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+	w.Header().Add("Vary", "Origin")
+
+	if !preflight {
+		return false
+	}
+
+	methods := requestMethod
+	if len(conf.Methods) > 0 {
+		methods = strings.Join(conf.Methods, ", ")
+	}
+
+	headers := r.Header.Get("Access-Control-Request-Headers")
+	if len(conf.Headers) > 0 {
+		headers = strings.Join(conf.Headers, ", ")
+	}
+
+	w.Header().Set("Access-Control-Allow-Methods", methods)
+
+	if headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return true
+}
+
+func mergeCORS(global, operation config.CORS) config.CORS {
+	if len(operation.Origins) > 0 {
+		global.Origins = operation.Origins
+	}
+
+	if len(operation.Methods) > 0 {
+		global.Methods = operation.Methods
+	}
+
+	if len(operation.Headers) > 0 {
+		global.Headers = operation.Headers
+	}
+
+	return global
+}
+
+func corsAllowOrigin(origins []string, origin string) (string, bool) {
+	for _, o := range origins {
+		if o == "*" {
+			return "*", true
+		}
+
+		if o == origin {
+			return origin, true
+		}
+	}
+
+	return "", false
+}
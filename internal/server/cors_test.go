@@ -0,0 +1,158 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/config"
+)
+
+func TestServer_Handler_CORS(t *testing.T) {
+	s := newServer(t, "./testdata/cors.yml")
+	s.Config.CORS = config.CORS{
+		Origins: []string{"https://app.example.com"},
+		Methods: []string{"GET", "POST"},
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		header      map[string]string
+		statusCode  int
+		allowOrigin string
+		allowMethod string
+	}{
+		{
+			name:        "global origin",
+			method:      http.MethodGet,
+			path:        "/users",
+			header:      map[string]string{"Origin": "https://app.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "https://app.example.com",
+		},
+		{
+			name:        "global preflight",
+			method:      http.MethodOptions,
+			path:        "/users",
+			header:      map[string]string{"Origin": "https://app.example.com", "Access-Control-Request-Method": "GET"},
+			statusCode:  http.StatusNoContent,
+			allowOrigin: "https://app.example.com",
+			allowMethod: "GET, POST",
+		},
+		{
+			name:        "not allowed origin",
+			method:      http.MethodGet,
+			path:        "/users",
+			header:      map[string]string{"Origin": "https://public.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "",
+		},
+		{
+			name:        "path specific origin",
+			method:      http.MethodGet,
+			path:        "/public",
+			header:      map[string]string{"Origin": "https://public.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "https://public.example.com",
+		},
+		{
+			name:        "path specific preflight",
+			method:      http.MethodOptions,
+			path:        "/public",
+			header:      map[string]string{"Origin": "https://public.example.com", "Access-Control-Request-Method": "GET"},
+			statusCode:  http.StatusNoContent,
+			allowOrigin: "https://public.example.com",
+			allowMethod: "GET",
+		},
+		{
+			name:        "global origin on path with specific origin",
+			method:      http.MethodGet,
+			path:        "/public",
+			header:      map[string]string{"Origin": "https://app.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.allowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			require.Equal(t, tc.allowMethod, w.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
+func TestServer_Handler_CORS_Wildcard(t *testing.T) {
+	tests := []struct {
+		name         string
+		cors         config.CORS
+		method       string
+		header       map[string]string
+		statusCode   int
+		allowOrigin  string
+		allowHeaders string
+	}{
+		{
+			name:        "disabled by default",
+			method:      http.MethodGet,
+			header:      map[string]string{"Origin": "https://app.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "",
+		},
+		{
+			name:        "simple request",
+			cors:        config.CORS{Origins: []string{"*"}},
+			method:      http.MethodGet,
+			header:      map[string]string{"Origin": "https://app.example.com"},
+			statusCode:  http.StatusOK,
+			allowOrigin: "*",
+		},
+		{
+			name:   "preflight request",
+			cors:   config.CORS{Origins: []string{"*"}, Headers: []string{"Authorization", "Content-Type"}},
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "X-Request-Id",
+			},
+			statusCode:   http.StatusNoContent,
+			allowOrigin:  "*",
+			allowHeaders: "Authorization, Content-Type",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, "./testdata/cors.yml")
+			s.Config.CORS = tc.cors
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, "/users", nil)
+
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.allowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			require.Equal(t, tc.allowHeaders, w.Header().Get("Access-Control-Allow-Headers"))
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/neotoolkit/dummy/internal/api"
 	"github.com/neotoolkit/dummy/internal/logger"
@@ -13,44 +16,172 @@ import (
 
 // Handlers -.
 type Handlers struct {
-	API    api.API
+	API api.API
+	// Live replaces API of each request when set, e.g. with API reloaded on specification change
+	Live   *api.Holder
 	Logger *logger.Logger
+	// Rand is used for weighted example selection
+	Rand *rand.Rand
+	// Transform is invoked before response body serialization, may be nil
+	Transform TransformFunc
+	// MatchLog receives MatchEvent of each request as JSON line, events are not written when nil
+	MatchLog io.Writer
 }
 
+// TransformFunc returns modified response body of the matched operation, it is invoked for bodies of all
+// media types: string of text response, []byte of binary one and decoded example otherwise. Body of large
+// generated array is api.Items producing elements on demand, returning it as is keeps response streamed,
+// while Items.Slice returns all of its elements.
+type TransformFunc func(op api.Operation, resp api.Response, body interface{}) interface{}
+
 // NewHandlers returns a new instance of Handlers
-func NewHandlers(api api.API, l *logger.Logger) Handlers {
+func NewHandlers(a api.API, l *logger.Logger) Handlers {
 	return Handlers{
-		API:    api,
+		API:    a,
 		Logger: l,
+		Rand:   api.NewRand(time.Now().UnixNano()),
+	}
+}
+
+// snapshot returns server with API loaded from Live holder, so request is served by same API
+// even if it is replaced meanwhile
+func (s *Server) snapshot() *Server {
+	if nil == s.Handlers.Live {
+		return s
 	}
+
+	snapshot := *s
+	snapshot.Handlers.API = s.Handlers.Live.Load()
+
+	return &snapshot
 }
 
 // Handler -.
 func (s *Server) Handler(w http.ResponseWriter, r *http.Request) {
+	s = s.snapshot()
+
+	path := RemoveFragment(r.URL.Path)
+
+	if r.Method == http.MethodHead {
+		w = headResponseWriter{w}
+	}
+
+	if s.cors(w, r, path) {
+		return
+	}
+
+	if r.Method == http.MethodOptions && s.options(w, path) {
+		return
+	}
+
 	if setStatusCode(w, r.Header.Get("X-Set-Status-Code")) {
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
-	path := RemoveFragment(r.URL.Path)
+	if !s.delay(r) {
+		return
+	}
+
+	w, done := s.chaos(w, r)
+	if done {
+		return
+	}
 
-	response, ok, err := s.Handlers.Get(path, r.Method, r.Body)
+	operation, response, ok, err := s.Handlers.Get(api.FindResponseParams{
+		Path:             path,
+		Method:           r.Method,
+		Query:            r.URL.Query(),
+		Accept:           r.Header.Get("Accept"),
+		ContentType:      r.Header.Get("Content-Type"),
+		Header:           r.Header,
+		Client:           r.RemoteAddr,
+		Body:             r.Body,
+		PreferExample:    PreferExample(r.Header.Get("Prefer")),
+		PreferStatusCode: PreferStatusCode(r.Header.Get("Prefer")),
+		Variant:          r.Header.Get("X-Dummy-Variant"),
+	})
 	if ok {
-		if _, ok := err.(*json.SyntaxError); ok || errors.Is(err, api.ErrEmptyRequireField) {
-			w.WriteHeader(http.StatusBadRequest)
+		if isNotAcceptable(err) {
+			s.fail(w, r, http.StatusNotAcceptable, err)
+
+			return
+		}
+
+		if errors.Is(err, api.ErrUnauthorized) {
+			s.fail(w, r, http.StatusUnauthorized, err)
+
+			return
+		}
+
+		var rateLimitError *api.RateLimitError
+		if errors.As(err, &rateLimitError) {
+			w.Header().Set("Retry-After", rateLimitError.Seconds())
+			s.fail(w, r, http.StatusTooManyRequests, err)
+
+			return
+		}
+
+		if isBadRequest(err) {
+			s.fail(w, r, validationStatusCode(err), err)
+
+			return
+		}
+
+		for _, h := range response.Headers {
+			if v := h.Value(); v != "" {
+				w.Header().Set(h.Name, v)
+			}
+		}
+
+		key := r.Header.Get("X-Example")
+		if key == "" {
+			key = response.ExampleKey
+		}
+
+		if key == "" {
+			key = response.WeightedExampleKey(s.Handlers.Rand)
+		}
+
+		resp := response.ExampleValue(key)
+
+		if s.Handlers.Transform != nil {
+			resp = s.Handlers.Transform(operation, response, resp)
+		}
+
+		if response.MediaType == api.MediaTypeOctetStream {
+			s.binary(w, r, response, resp)
+
+			return
+		}
+
+		if response.MediaType != "" && response.MediaType != api.MediaTypeJSON && !api.IsXML(response.MediaType) {
+			s.text(w, response, resp)
 
 			return
 		}
 
+		if api.IsXML(response.MediaType) {
+			w.Header().Set("Content-Type", response.MediaType)
+		}
+
 		w.WriteHeader(response.StatusCode)
-		resp := response.ExampleValue(r.Header.Get("X-Example"))
 
 		if nil == resp {
 			return
 		}
 
-		bytes, err := json.Marshal(resp)
+		// large generated array is written element by element
+		if items, ok := resp.(api.Items); ok && !api.IsXML(response.MediaType) {
+			if err := items.EncodeJSON(w); err != nil {
+				s.Logger.Error().Err(err).Msg("write response")
+			}
+
+			return
+		}
+
+		bytes, err := marshal(response, resp)
 		if err != nil {
 			s.Logger.Error().Err(err).Msg("serialize response")
 		}
@@ -63,29 +194,279 @@ func (s *Server) Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusNotFound)
+	if path == "" && s.Config.RootHealth {
+		s.rootHealth(w)
+
+		return
+	}
+
+	var findResponseError *api.FindResponseError
+	if s.Handlers.API.Upstream != nil && (errors.As(err, &findResponseError) || errors.Is(err, api.ErrMethodNotAllowed)) {
+		s.proxy(w, r)
+
+		return
+	}
+
+	if errors.Is(err, api.ErrMethodNotAllowed) {
+		w.Header().Set("Allow", strings.Join(s.Handlers.API.Methods(path), ", "))
+		s.fail(w, r, http.StatusMethodNotAllowed, err)
+
+		return
+	}
+
+	s.fail(w, r, http.StatusNotFound, err)
 }
 
-// Get -.
-func (h Handlers) Get(path, method string, body io.ReadCloser) (api.Response, bool, error) {
-	response, err := h.API.FindResponse(api.FindResponseParams{
-		Path:   path,
-		Method: method,
-		Body:   body,
-	})
+// ErrorBody is JSON body of response to invalid request
+type ErrorBody struct {
+	Error string `json:"error"`
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	// Violations list all violations of request body, first of them is described by fields above
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Violation describes invalid field of request body
+type Violation struct {
+	Error string `json:"error"`
+	Field string `json:"field"`
+	Code  string `json:"code"`
+}
+
+// NewErrorBody returns body of response to invalid request with field and code of validation error
+// and violations of request body
+func NewErrorBody(err error) ErrorBody {
+	body := ErrorBody{
+		Error: err.Error(),
+	}
+
+	var validationError *api.ValidationError
+	if !errors.As(err, &validationError) {
+		return body
+	}
+
+	body.Field = validationError.Field
+	body.Code = validationError.Code
+
+	if validationError.In != "" || validationError.Malformed() {
+		return body
+	}
+
+	violations := validationError.Violations
+	if len(violations) == 0 {
+		violations = []*api.ValidationError{validationError}
+	}
+
+	for _, v := range violations {
+		body.Violations = append(body.Violations, Violation{Error: v.Error(), Field: v.Field, Code: v.Code})
+	}
+
+	return body
+}
+
+// badRequest writes legacy response with JSON body describing validation error
+func (s *Server) badRequest(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(validationStatusCode(err))
+
+	bytes, err := json.Marshal(NewErrorBody(err))
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("serialize error")
+	}
+
+	_, err = w.Write(bytes)
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("write error")
+	}
+}
+
+// marshal returns response body serialized for media type of response
+func marshal(response api.Response, body interface{}) ([]byte, error) {
+	if api.IsXML(response.MediaType) {
+		return marshalXML(response, body)
+	}
+
+	return json.Marshal(body)
+}
+
+// DelayHeader overrides simulated latency per request, e.g. `500ms` or `100ms-500ms`
+const DelayHeader = "X-Mock-Delay"
+
+// delay sleeps for simulated latency, false is returned if request is canceled meanwhile
+func (s *Server) delay(r *http.Request) bool {
+	latency := s.Handlers.API.Latency
+
+	if header := r.Header.Get(DelayHeader); header != "" {
+		l, err := api.ParseLatency(header)
+		if err != nil {
+			s.Logger.Warn().Err(err).Msg("parse delay header")
+		} else {
+			latency = l
+		}
+	}
+
+	return sleep(r, latency.Duration(s.Handlers.Rand))
+}
+
+// sleep waits for d, false is returned if request is canceled meanwhile
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// text writes string body of response with media type other than JSON as is
+func (s *Server) text(w http.ResponseWriter, response api.Response, body interface{}) {
+	w.Header().Set("Content-Type", response.MediaType)
+	w.WriteHeader(response.StatusCode)
+
+	_, err := w.Write(rawBody(body))
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("write response")
+	}
+}
+
+// rawBody returns bytes of string or []byte body, body of other type is empty
+func rawBody(body interface{}) []byte {
+	switch b := body.(type) {
+	case []byte:
+		return b
+	case string:
+		return []byte(b)
+	default:
+		return nil
+	}
+}
+
+// rootHealth responds to load balancer probes of root path
+func (s *Server) rootHealth(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+
+	if s.Config.RootHealthBody == "" {
+		return
+	}
+
+	_, err := w.Write([]byte(s.Config.RootHealthBody))
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("write root health response")
+	}
+}
+
+// ExamplesPath is admin route returning all named examples of an operation
+const ExamplesPath = "/__dummy/examples"
+
+// ExamplesHandler returns all named examples of an operation as a single JSON object.
+// Operation is selected by `method` and `path` query parameters.
+func (s *Server) ExamplesHandler(w http.ResponseWriter, r *http.Request) {
+	s = s.snapshot()
+
+	query := r.URL.Query()
+
+	method := query.Get("method")
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	examples, err := s.Handlers.API.FindExamples(method, RemoveFragment(query.Get("path")))
 	if err != nil {
-		if errors.Is(err, api.ErrEmptyRequireField) {
-			return api.Response{}, true, err
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	bytes, err := json.Marshal(examples)
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("serialize examples")
+	}
+
+	_, err = w.Write(bytes)
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("write examples")
+	}
+}
+
+// Get returns operation matching request and its response, false is returned when no operation matches request
+func (h Handlers) Get(params api.FindResponseParams) (api.Operation, api.Response, bool, error) {
+	operation, response, err := h.API.Match(params)
+
+	h.logMatch(params, response, err)
+
+	if err != nil {
+		if isBadRequest(err) || isNotAcceptable(err) || errors.Is(err, api.ErrUnauthorized) || isRateLimited(err) {
+			return operation, api.Response{}, true, err
 		}
 
-		if _, ok := err.(*json.SyntaxError); ok {
-			return api.Response{}, true, err
+		return api.Operation{}, api.Response{}, false, err
+	}
+
+	return operation, response, true, nil
+}
+
+// PreferExample returns example name of `Prefer` header value, e.g. `example=empty_list`
+func PreferExample(prefer string) string {
+	return preference(prefer, "example")
+}
+
+// PreferStatusCode returns status code of `Prefer` header value, e.g. `code=404`, or 0 if it is not specified
+func PreferStatusCode(prefer string) int {
+	code, err := strconv.Atoi(preference(prefer, "code"))
+	if err != nil {
+		return 0
+	}
+
+	return code
+}
+
+// preference returns value of preference with name from `Prefer` header value
+func preference(prefer, name string) string {
+	for _, pref := range strings.FieldsFunc(prefer, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, ok := cut(strings.TrimSpace(pref), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			return strings.Trim(strings.TrimSpace(value), `"`)
 		}
+	}
 
-		return api.Response{}, false, err
+	return ""
+}
+
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
 	}
 
-	return response, true, nil
+	return s, "", false
+}
+
+func isNotAcceptable(err error) bool {
+	var statusCodeError *api.StatusCodeError
+
+	return errors.As(err, &statusCodeError)
+}
+
+// isRateLimited reports whether request exceeds rate limit
+func isRateLimited(err error) bool {
+	var rateLimitError *api.RateLimitError
+
+	return errors.As(err, &rateLimitError)
+}
+
+// isBadRequest reports whether error is request validation error, all of them are wrapped by api.ValidationError
+func isBadRequest(err error) bool {
+	var validationError *api.ValidationError
+
+	return errors.As(err, &validationError)
 }
 
 func setStatusCode(w http.ResponseWriter, statusCode string) bool {
@@ -0,0 +1,876 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/config"
+	"github.com/neotoolkit/dummy/internal/logger"
+	"github.com/neotoolkit/dummy/internal/parse"
+	"github.com/neotoolkit/dummy/internal/server"
+)
+
+func newServer(t *testing.T, path string) *server.Server {
+	t.Helper()
+
+	a, err := parse.Parse(path)
+	require.NoError(t, err)
+
+	l := logger.NewLogger("")
+
+	return server.NewServer(config.Server{}, l, server.NewHandlers(a, l))
+}
+
+func TestServer_ExamplesHandler(t *testing.T) {
+	s := newServer(t, "./testdata/examples.yml")
+
+	tests := []struct {
+		name       string
+		target     string
+		statusCode int
+		want       map[string]interface{}
+	}{
+		{
+			name:       "all named examples",
+			target:     server.ExamplesPath + "?method=GET&path=/users/1",
+			statusCode: http.StatusOK,
+			want: map[string]interface{}{
+				"elon": map[string]interface{}{
+					"id":        "e1afccea-5168-4735-84d4-cb96f6fb5d25",
+					"firstName": "Elon",
+					"lastName":  "Musk",
+				},
+				"sergey": map[string]interface{}{
+					"id":        "472063cc-4c83-11ec-81d3-0242ac130003",
+					"firstName": "Sergey",
+					"lastName":  "Brin",
+				},
+			},
+		},
+		{
+			name:       "default method",
+			target:     server.ExamplesPath + "?path=/users/1",
+			statusCode: http.StatusOK,
+			want: map[string]interface{}{
+				"elon": map[string]interface{}{
+					"id":        "e1afccea-5168-4735-84d4-cb96f6fb5d25",
+					"firstName": "Elon",
+					"lastName":  "Musk",
+				},
+				"sergey": map[string]interface{}{
+					"id":        "472063cc-4c83-11ec-81d3-0242ac130003",
+					"firstName": "Sergey",
+					"lastName":  "Brin",
+				},
+			},
+		},
+		{
+			name:       "not specified operation",
+			target:     server.ExamplesPath + "?method=DELETE&path=/users/1",
+			statusCode: http.StatusNotFound,
+			want:       nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+
+			s.ExamplesHandler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+
+			if tc.want == nil {
+				return
+			}
+
+			var got map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServer_Handler_RootHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		body       string
+		path       string
+		statusCode int
+		want       string
+	}{
+		{
+			name:       "disabled",
+			enabled:    false,
+			path:       "/",
+			statusCode: http.StatusNotFound,
+			want: `{"type":"about:blank","title":"Not Found","status":404,` +
+				`"detail":"not specified operation: GET /","instance":"/"}`,
+		},
+		{
+			name:       "enabled",
+			enabled:    true,
+			path:       "/",
+			statusCode: http.StatusOK,
+			want:       "",
+		},
+		{
+			name:       "enabled with body",
+			enabled:    true,
+			body:       `{"status":"ok"}`,
+			path:       "/",
+			statusCode: http.StatusOK,
+			want:       `{"status":"ok"}`,
+		},
+		{
+			name:       "enabled, not root path",
+			enabled:    true,
+			path:       "/unknown",
+			statusCode: http.StatusNotFound,
+			want: `{"type":"about:blank","title":"Not Found","status":404,` +
+				`"detail":"not specified operation: GET /unknown","instance":"/unknown"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, "./testdata/examples.yml")
+			s.Config.RootHealth = tc.enabled
+			s.Config.RootHealthBody = tc.body
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Handler_EmptyStructures(t *testing.T) {
+	s := newServer(t, "./testdata/empty.yml")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "empty object",
+			path: "/object",
+			want: "{}",
+		},
+		{
+			name: "empty array",
+			path: "/array",
+			want: "[]",
+		},
+		{
+			name: "example without value",
+			path: "/examples",
+			want: "{}",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+			s.Handler(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Handler_Transform(t *testing.T) {
+	s := newServer(t, "./testdata/examples.yml")
+	s.Handlers.Transform = func(op api.Operation, resp api.Response, body interface{}) interface{} {
+		b, ok := body.(map[string]interface{})
+		if !ok {
+			return body
+		}
+
+		res := make(map[string]interface{}, len(b)+1)
+		for k, v := range b {
+			res[k] = v
+		}
+
+		res["operation"] = op.Method + " " + op.Path
+
+		return res
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set("X-Example", "elon")
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Equal(t, map[string]interface{}{
+		"id":        "e1afccea-5168-4735-84d4-cb96f6fb5d25",
+		"firstName": "Elon",
+		"lastName":  "Musk",
+		"operation": "GET /users/{userId}",
+	}, got)
+}
+
+func TestServer_Handler_Transform_Raw(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		path string
+		want string
+	}{
+		{
+			name: "text",
+			spec: "./testdata/text.yml",
+			path: "/page",
+			want: "<h1>Hello</h1> GET /page",
+		},
+		{
+			name: "binary",
+			spec: "./testdata/binary.yml",
+			path: "/file",
+			want: "0123456789 GET /file",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, tc.spec)
+			s.Handlers.Transform = func(op api.Operation, resp api.Response, body interface{}) interface{} {
+				switch b := body.(type) {
+				case string:
+					return b + " " + op.Method + " " + op.Path
+				case []byte:
+					return append(append([]byte{}, b...), " "+op.Method+" "+op.Path...)
+				default:
+					return body
+				}
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+			s.Handler(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Handler_Echo(t *testing.T) {
+	tests := []struct {
+		name string
+		echo bool
+		body string
+		want map[string]interface{}
+	}{
+		{
+			name: "echo disabled",
+			echo: false,
+			body: `{"firstName":"Alan","lastName":"Turing"}`,
+			want: map[string]interface{}{},
+		},
+		{
+			name: "echo enabled",
+			echo: true,
+			body: `{"firstName":"Alan","lastName":"Turing"}`,
+			want: map[string]interface{}{
+				"firstName": "Alan",
+				"lastName":  "Turing",
+			},
+		},
+		{
+			name: "echo enabled, unknown field",
+			echo: true,
+			body: `{"firstName":"Alan","role":"admin"}`,
+			want: map[string]interface{}{
+				"firstName": "Alan",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, "./testdata/echo.yml")
+			s.Handlers.API.Echo = tc.echo
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tc.body))
+
+			s.Handler(w, r)
+
+			require.Equal(t, http.StatusCreated, w.Code)
+
+			var got map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.NotEmpty(t, got["id"])
+			require.NotContains(t, got, "role")
+
+			for _, k := range []string{"firstName", "lastName"} {
+				want, ok := tc.want[k]
+				if !ok {
+					require.NotEqual(t, "Alan", got[k])
+					require.NotEqual(t, "Turing", got[k])
+
+					continue
+				}
+
+				require.Equal(t, want, got[k])
+			}
+		})
+	}
+}
+
+func TestServer_Handler_PreferExample(t *testing.T) {
+	s := newServer(t, "./testdata/examples.yml")
+
+	tests := []struct {
+		name   string
+		prefer string
+		want   string
+	}{
+		{
+			name:   "preferred example",
+			prefer: "example=sergey",
+			want:   "Sergey",
+		},
+		{
+			name:   "preferred example among other preferences",
+			prefer: `return=representation, example="sergey"`,
+			want:   "Sergey",
+		},
+		{
+			name:   "absent example falls back to default",
+			prefer: "example=larry",
+			want:   "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			r.Header.Set("Prefer", tc.prefer)
+
+			s.Handler(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var got map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+
+			if tc.want == "" {
+				require.Contains(t, []interface{}{"Elon", "Sergey"}, got["firstName"])
+
+				return
+			}
+
+			require.Equal(t, tc.want, got["firstName"])
+		})
+	}
+}
+
+func TestPreferExample(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefer string
+		want   string
+	}{
+		{
+			name:   "empty",
+			prefer: "",
+			want:   "",
+		},
+		{
+			name:   "example",
+			prefer: "example=empty_list",
+			want:   "empty_list",
+		},
+		{
+			name:   "quoted example with other preferences",
+			prefer: `respond-async; example="empty_list", wait=10`,
+			want:   "empty_list",
+		},
+		{
+			name:   "without example",
+			prefer: "return=minimal",
+			want:   "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, server.PreferExample(tc.prefer))
+		})
+	}
+}
+
+func TestServer_Handler_PreferStatusCode(t *testing.T) {
+	s := newServer(t, "./testdata/status-codes.yml")
+
+	tests := []struct {
+		name       string
+		prefer     string
+		statusCode int
+		want       string
+	}{
+		{
+			name:       "default response",
+			prefer:     "",
+			statusCode: http.StatusOK,
+			want:       `{"firstName":"Elon"}`,
+		},
+		{
+			name:       "preferred status code",
+			prefer:     "code=404",
+			statusCode: http.StatusNotFound,
+			want:       `{"message":"user not found"}`,
+		},
+		{
+			name:       "not specified status code",
+			prefer:     "code=500",
+			statusCode: http.StatusNotAcceptable,
+			want: `{"type":"about:blank","title":"Not Acceptable","status":406,` +
+				`"detail":"operation has no response with status code 500","instance":"/users/1"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			r.Header.Set("Prefer", tc.prefer)
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Handler_Accept(t *testing.T) {
+	s := newServer(t, "./testdata/accept.yml")
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		want        string
+	}{
+		{
+			name:        "without accept",
+			accept:      "",
+			contentType: "application/json",
+			want:        `{"firstName":"Elon"}`,
+		},
+		{
+			name:        "xml preferred over json",
+			accept:      "application/json;q=0.5, application/xml",
+			contentType: "application/xml",
+			want:        `<user><firstName>Elon</firstName></user>`,
+		},
+		{
+			name:        "json preferred over xml",
+			accept:      "application/json, application/xml;q=0.9",
+			contentType: "application/json",
+			want:        `{"firstName":"Elon"}`,
+		},
+		{
+			name:        "wildcard",
+			accept:      "*/*",
+			contentType: "application/json",
+			want:        `{"firstName":"Elon"}`,
+		},
+		{
+			name:        "type wildcard",
+			accept:      "text/html, application/*;q=0.8",
+			contentType: "application/json",
+			want:        `{"firstName":"Elon"}`,
+		},
+		{
+			name:        "not acceptable media type falls back to first one",
+			accept:      "text/html",
+			contentType: "application/json",
+			want:        `{"firstName":"Elon"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			r.Header.Set("Accept", tc.accept)
+
+			s.Handler(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Handler_Stateful(t *testing.T) {
+	s := newServer(t, "./testdata/crud.yml")
+	s.Handlers.API.Store = api.NewStore(api.NewRand(1))
+
+	do := func(t *testing.T, method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+
+		s.Handler(w, r)
+
+		return w
+	}
+
+	w := do(t, http.MethodPost, "/users", `{"id": "1", "firstName": "Alan"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"id": "1", "firstName": "Alan"}`, w.Body.String())
+
+	w = do(t, http.MethodPost, "/users", `{"firstName": "Ada"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotEmpty(t, created["id"])
+	require.Equal(t, "Ada", created["firstName"])
+
+	w = do(t, http.MethodGet, "/users", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list []map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 2)
+
+	w = do(t, http.MethodGet, "/users/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"id": "1", "firstName": "Alan"}`, w.Body.String())
+
+	w = do(t, http.MethodDelete, "/users/1", "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = do(t, http.MethodGet, "/users/1", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(t, http.MethodDelete, "/users/1", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(t, http.MethodGet, "/users", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+}
+
+func TestServer_Handler_Latency(t *testing.T) {
+	const latency = 20 * time.Millisecond
+
+	s := newServer(t, "./testdata/status-codes.yml")
+	s.Handlers.API.Latency = api.Latency{Min: latency}
+
+	t.Run("configured latency", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+
+		start := time.Now()
+		s.Handler(w, r)
+
+		require.GreaterOrEqual(t, time.Since(start), latency)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("delay header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		r.Header.Set(server.DelayHeader, "40ms")
+
+		start := time.Now()
+		s.Handler(w, r)
+
+		require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("canceled request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/users/1", nil).WithContext(ctx)
+		r.Header.Set(server.DelayHeader, "1m")
+
+		s.Handler(w, r)
+
+		require.Empty(t, w.Body.String())
+	})
+}
+
+func TestServer_Handler_Form(t *testing.T) {
+	s := newServer(t, "./testdata/form.yml")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("username=elon&password=mars"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"token":"secret"}`, w.Body.String())
+}
+
+func TestServer_Handler_Security(t *testing.T) {
+	s := newServer(t, "./testdata/security.yml")
+
+	tests := []struct {
+		name          string
+		authorization string
+		statusCode    int
+	}{
+		{
+			name:          "with credentials",
+			authorization: "Bearer token",
+			statusCode:    http.StatusOK,
+		},
+		{
+			name:          "without credentials",
+			authorization: "",
+			statusCode:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/profile", nil)
+
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+		})
+	}
+}
+
+func TestServer_Handler_ValidationError(t *testing.T) {
+	s := newServer(t, "./testdata/echo.yml")
+	s.Config.LegacyErrors = true
+
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		want       string
+	}{
+		{
+			name:       "missing required field",
+			body:       `{"lastName": "Musk"}`,
+			statusCode: http.StatusUnprocessableEntity,
+			want: `{"error": "empty require field", "field": "firstName", "code": "required_field_missing", "violations": [
+				{"error": "empty require field", "field": "firstName", "code": "required_field_missing"}
+			]}`,
+		},
+		{
+			name:       "invalid type",
+			body:       `{"firstName": 1}`,
+			statusCode: http.StatusUnprocessableEntity,
+			want: `{"error": "field firstName must be string", "field": "firstName", "code": "invalid_type", "violations": [
+				{"error": "field firstName must be string", "field": "firstName", "code": "invalid_type"}
+			]}`,
+		},
+		{
+			name:       "malformed body",
+			body:       `{"firstName":`,
+			statusCode: http.StatusBadRequest,
+			want:       `{"error": "unexpected EOF", "field": "", "code": "malformed_body"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tc.body))
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.want, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Handler_Violations(t *testing.T) {
+	s := newServer(t, "./testdata/validation.yml")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(
+		`{"email": "elon", "address": {"zip": "9430"}, "tags": ["admin", "root"]}`,
+	))
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "empty require field",
+		"instance": "/users",
+		"field": "address.city",
+		"code": "required_field_missing",
+		"violations": [
+			{"error": "empty require field", "field": "address.city", "code": "required_field_missing"},
+			{"error": "field address.zip does not match pattern ^[0-9]{5}$", "field": "address.zip", "code": "pattern_mismatch"},
+			{"error": "field email must be email", "field": "email", "code": "invalid_format"},
+			{"error": "field tags[1] has value root not allowed by enum", "field": "tags[1]", "code": "not_in_enum"}
+		]
+	}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(
+		`{"email": "elon@example.com", "address": {"city": "Austin", "zip": "78701"}, "tags": ["admin"]}`,
+	))
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestServer_Handler_Headers(t *testing.T) {
+	s := newServer(t, "./testdata/headers.yml")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "/users/1", w.Header().Get("Location"))
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "100", w.Header().Get("X-RateLimit-Limit"))
+	require.NotEmpty(t, w.Header().Get("X-Total-Count"))
+}
+
+func TestServer_Handler_LargeArray(t *testing.T) {
+	s := newServer(t, "./testdata/large-array.yml")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got []map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Len(t, got, 10000)
+	require.NotEmpty(t, got[9999]["id"])
+}
+
+func TestServer_Handler_Variant(t *testing.T) {
+	s := newServer(t, "./testdata/one-of.yml")
+
+	for _, variant := range []string{"Cat", "Dog"} {
+		t.Run(variant, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/pets/1", nil)
+			r.Header.Set("X-Dummy-Variant", variant)
+
+			s.Handler(w, r)
+
+			var got map[string]interface{}
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Equal(t, strings.ToLower(variant), got["kind"])
+		})
+	}
+}
+
+func TestServer_Handler_DefaultResponse(t *testing.T) {
+	s := newServer(t, "./testdata/default-response.yml")
+
+	tests := []struct {
+		name       string
+		path       string
+		prefer     string
+		statusCode int
+		want       string
+	}{
+		{
+			name:       "specific response",
+			path:       "/users/1",
+			prefer:     "",
+			statusCode: http.StatusOK,
+			want:       `{"firstName":"Elon"}`,
+		},
+		{
+			name:       "default response of preferred status code",
+			path:       "/users/1",
+			prefer:     "code=503",
+			statusCode: http.StatusServiceUnavailable,
+			want:       `{"message":"unexpected error"}`,
+		},
+		{
+			name:       "default response only",
+			path:       "/health",
+			prefer:     "",
+			statusCode: http.StatusOK,
+			want:       `{"status":"ok"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.Header.Set("Prefer", tc.prefer)
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
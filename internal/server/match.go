@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+// MatchEvent describes matching of request to operation, written to Handlers.MatchLog
+type MatchEvent struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Operation is path pattern of matched operation, e.g. `/users/{id}`, empty when no operation matches
+	Operation string `json:"operation,omitempty"`
+	// StatusCode is status code of chosen response, zero when response is not chosen
+	StatusCode int `json:"statusCode,omitempty"`
+	// Invalid is set when request does not pass validation of matched operation
+	Invalid bool   `json:"invalid,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// matchLogMu serializes writes of events, since requests are served concurrently and copies of Handlers
+// share MatchLog
+//
+//nolint:gochecknoglobals // lock of MatchLog shared by all copies of Handlers
+var matchLogMu sync.Mutex
+
+// logMatch writes event of matching request as JSON line to MatchLog, nothing is written when it is nil
+func (h Handlers) logMatch(params api.FindResponseParams, response api.Response, err error) {
+	if nil == h.MatchLog {
+		return
+	}
+
+	event := MatchEvent{
+		Method:     params.Method,
+		Path:       params.Path,
+		StatusCode: response.StatusCode,
+		Invalid:    isBadRequest(err),
+	}
+
+	if operation, ok := h.API.FindOperation(params.Method, params.Path); ok {
+		event.Operation = operation.Path
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	matchLogMu.Lock()
+	defer matchLogMu.Unlock()
+
+	_, _ = h.MatchLog.Write(append(line, '\n'))
+}
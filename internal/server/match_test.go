@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/server"
+)
+
+func TestServer_Handler_MatchLog(t *testing.T) {
+	s := newServer(t, "./testdata/form.yml")
+
+	var buf bytes.Buffer
+
+	s.Handlers.MatchLog = &buf
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want server.MatchEvent
+	}{
+		{
+			name: "matched",
+			path: "/login",
+			body: "username=elon&password=mars",
+			want: server.MatchEvent{
+				Method:     http.MethodPost,
+				Path:       "/login",
+				Operation:  "/login",
+				StatusCode: http.StatusOK,
+			},
+		},
+		{
+			name: "invalid",
+			path: "/login",
+			body: "username=elon",
+			want: server.MatchEvent{
+				Method:    http.MethodPost,
+				Path:      "/login",
+				Operation: "/login",
+				Invalid:   true,
+				Error:     "empty require field",
+			},
+		},
+		{
+			name: "unmatched",
+			path: "/logout",
+			body: "",
+			want: server.MatchEvent{
+				Method: http.MethodPost,
+				Path:   "/logout",
+				Error:  "not specified operation: POST /logout",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			s.Handler(w, r)
+
+			var got server.MatchEvent
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestServer_Handler_MatchLog_Concurrent(t *testing.T) {
+	s := newServer(t, "./testdata/form.yml")
+
+	var buf bytes.Buffer
+
+	s.Handlers.MatchLog = &buf
+
+	const n = 50
+
+	var wg sync.WaitGroup
+
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("username=elon&password=mars"))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			s.Handler(w, r)
+		}()
+	}
+
+	wg.Wait()
+
+	lines := 0
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var got server.MatchEvent
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+		require.Equal(t, "/login", got.Operation)
+
+		lines++
+	}
+
+	require.Equal(t, n, lines)
+}
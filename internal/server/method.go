@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// options responds to OPTIONS request of path without OPTIONS operation with methods allowed for path,
+// false is returned for unknown path
+func (s *Server) options(w http.ResponseWriter, path string) bool {
+	if _, ok := s.Handlers.API.FindOperation(http.MethodOptions, path); ok {
+		return false
+	}
+
+	methods := s.Handlers.API.Methods(path)
+	if len(methods) == 0 {
+		return false
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	w.WriteHeader(http.StatusNoContent)
+
+	return true
+}
+
+// headResponseWriter writes headers of response to HEAD request and discards body
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write -.
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
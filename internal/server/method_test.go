@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Handler_Options(t *testing.T) {
+	s := newServer(t, "./testdata/crud.yml")
+
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		allow      string
+	}{
+		{
+			name:       "collection",
+			path:       "/users",
+			statusCode: http.StatusNoContent,
+			allow:      "GET, HEAD, OPTIONS, POST",
+		},
+		{
+			name:       "item",
+			path:       "/users/42",
+			statusCode: http.StatusNoContent,
+			allow:      "DELETE, GET, HEAD, OPTIONS",
+		},
+		{
+			name:       "unknown path",
+			path:       "/orgs",
+			statusCode: http.StatusNotFound,
+			allow:      "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodOptions, tc.path, nil)
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.allow, w.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestServer_Handler_Head(t *testing.T) {
+	s := newServer(t, "./testdata/crud.yml")
+
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+	}{
+		{
+			name:       "GET operation",
+			path:       "/users",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "unknown path",
+			path:       "/orgs",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodHead, tc.path, nil)
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Empty(t, w.Body.String())
+		})
+	}
+
+	w := httptest.NewRecorder()
+	s.Handler(w, httptest.NewRequest(http.MethodHead, "/users", nil))
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func TestServer_Handler_MethodNotAllowed(t *testing.T) {
+	s := newServer(t, "./testdata/crud.yml")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodDelete, "/users", nil)
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, "GET, HEAD, OPTIONS, POST", w.Header().Get("Allow"))
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodDelete, "/orgs", nil)
+
+	s.Handler(w, r)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Empty(t, w.Header().Get("Allow"))
+}
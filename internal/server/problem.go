@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+// ProblemMediaType is media type of error responses, see RFC 7807
+const ProblemMediaType = "application/problem+json"
+
+// problemTypeBlank is type of problem without additional semantics beyond status code
+const problemTypeBlank = "about:blank"
+
+// Problem is RFC 7807 problem details of error response.
+// Validation errors extend it with field and code of first violation and all violations of request body.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Field      string      `json:"field,omitempty"`
+	Code       string      `json:"code,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// NewProblem returns problem details of error response with status code to request of instance path,
+// error is detail of problem, it may be nil
+func NewProblem(statusCode int, err error, instance string) Problem {
+	p := Problem{
+		Type:     problemTypeBlank,
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Instance: instance,
+	}
+
+	if nil == err {
+		return p
+	}
+
+	body := NewErrorBody(err)
+
+	p.Detail = body.Error
+	p.Field = body.Field
+	p.Code = body.Code
+	p.Violations = body.Violations
+
+	return p
+}
+
+// fail writes error response of status code as problem details. Legacy error responses have no body,
+// except JSON error body of validation error.
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if s.Config.LegacyErrors {
+		if isBadRequest(err) {
+			s.badRequest(w, err)
+
+			return
+		}
+
+		w.WriteHeader(statusCode)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemMediaType)
+	w.WriteHeader(statusCode)
+
+	bytes, err := json.Marshal(NewProblem(statusCode, err, r.URL.Path))
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("serialize problem")
+	}
+
+	if _, err := w.Write(bytes); err != nil {
+		s.Logger.Error().Err(err).Msg("write problem")
+	}
+}
+
+// validationStatusCode returns status code of response to invalid request: 400 for malformed body
+// or invalid parameters and 422 for request body violating its schema
+func validationStatusCode(err error) int {
+	var validationError *api.ValidationError
+	if errors.As(err, &validationError) && validationError.In == "" && !validationError.Malformed() {
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusBadRequest
+}
@@ -0,0 +1,98 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/server"
+)
+
+func TestNewProblem(t *testing.T) {
+	require.Equal(t, server.Problem{
+		Type:     "about:blank",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "not specified operation: GET /users",
+		Instance: "/users",
+	}, server.NewProblem(http.StatusNotFound, errors.New("not specified operation: GET /users"), "/users"))
+
+	require.Equal(t, server.Problem{
+		Type:   "about:blank",
+		Title:  "Unauthorized",
+		Status: http.StatusUnauthorized,
+	}, server.NewProblem(http.StatusUnauthorized, nil, ""))
+}
+
+func TestServer_Handler_Problem(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		legacy     bool
+		statusCode int
+		want       string
+	}{
+		{
+			name:       "not found",
+			method:     http.MethodGet,
+			path:       "/orgs",
+			statusCode: http.StatusNotFound,
+			want: `{"type": "about:blank", "title": "Not Found", "status": 404,
+				"detail": "not specified operation: GET /orgs", "instance": "/orgs"}`,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodDelete,
+			path:       "/users",
+			statusCode: http.StatusMethodNotAllowed,
+			want: `{"type": "about:blank", "title": "Method Not Allowed", "status": 405,
+				"detail": "method not allowed: DELETE /users", "instance": "/users"}`,
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			path:       "/users",
+			body:       `{"firstName":`,
+			statusCode: http.StatusBadRequest,
+			want: `{"type": "about:blank", "title": "Bad Request", "status": 400,
+				"detail": "unexpected EOF", "instance": "/users", "code": "malformed_body"}`,
+		},
+		{
+			name:       "legacy not found",
+			method:     http.MethodGet,
+			path:       "/orgs",
+			legacy:     true,
+			statusCode: http.StatusNotFound,
+			want:       "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, "./testdata/echo.yml")
+			s.Config.LegacyErrors = tc.legacy
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+
+			if tc.legacy {
+				require.Empty(t, w.Body.String())
+
+				return
+			}
+
+			require.Equal(t, server.ProblemMediaType, w.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.want, w.Body.String())
+		})
+	}
+}
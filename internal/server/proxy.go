@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httputil"
+)
+
+// proxy forwards request of not specified operation to upstream server and streams its response back.
+// Method, headers and body of request are preserved.
+func (s *Server) proxy(w http.ResponseWriter, r *http.Request) {
+	upstream := s.Handlers.API.Upstream
+
+	p := httputil.NewSingleHostReverseProxy(upstream)
+
+	director := p.Director
+	p.Director = func(req *http.Request) {
+		director(req)
+		req.Host = upstream.Host
+	}
+
+	p.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		s.Logger.Error().Err(err).Msg("proxy request")
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	if timeout := s.Handlers.API.UpstreamTimeout; timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		r = r.WithContext(ctx)
+	}
+
+	// content type of mocked responses is replaced by upstream one
+	w.Header().Del("Content-Type")
+
+	p.ServeHTTP(w, r)
+}
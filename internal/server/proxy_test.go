@@ -0,0 +1,66 @@
+package server_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Handler_Proxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.RequestURI() + " " + r.Header.Get("X-Request-Id") + " " + string(body)))
+	}))
+	defer upstream.Close()
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	s := newServer(t, "./testdata/echo.yml")
+	s.Handlers.API.Upstream = u
+	s.Handlers.API.UpstreamTimeout = 50 * time.Millisecond
+
+	t.Run("not specified operation is proxied", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/orders/1?expand=items", strings.NewReader(`{"id":1}`))
+		r.Header.Set("X-Request-Id", "42")
+
+		s.Handler(w, r)
+
+		require.Equal(t, http.StatusTeapot, w.Code)
+		require.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+		require.Equal(t, `PUT /orders/1?expand=items 42 {"id":1}`, w.Body.String())
+	})
+
+	t.Run("specified operation is mocked", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"firstName":"Elon"}`))
+
+		s.Handler(w, r)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/slow", nil)
+
+		s.Handler(w, r)
+
+		require.Equal(t, http.StatusBadGateway, w.Code)
+	})
+}
@@ -0,0 +1,51 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+func TestServer_Handler_RateLimit(t *testing.T) {
+	const requests = 3
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := newServer(t, "./testdata/crud.yml")
+	s.Handlers.API.RateLimiter = api.NewRateLimiter(api.RateLimit{Requests: requests, Window: time.Minute})
+	s.Handlers.API.Clock = api.ClockFunc(func() time.Time {
+		return now
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+		s.Handler(w, r)
+
+		return w
+	}
+
+	for i := 0; i < requests; i++ {
+		w := serve()
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Header().Get("Retry-After"))
+	}
+
+	now = now.Add(15500 * time.Millisecond)
+
+	w := serve()
+
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "45", w.Header().Get("Retry-After"))
+
+	now = now.Add(45 * time.Second)
+
+	require.Equal(t, http.StatusOK, serve().Code)
+}
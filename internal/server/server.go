@@ -2,8 +2,11 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 
+	"github.com/neotoolkit/dummy/internal/api"
 	"github.com/neotoolkit/dummy/internal/config"
 	"github.com/neotoolkit/dummy/internal/logger"
 	"github.com/neotoolkit/dummy/internal/middleware"
@@ -26,17 +29,57 @@ func NewServer(config config.Server, l *logger.Logger, h Handlers) *Server {
 	}
 }
 
-// Run -.
-func (s *Server) Run() error {
-	mux := http.NewServeMux()
+// Option configures server returned by New
+type Option func(*Server)
 
-	mux.HandleFunc("/", s.Handler)
+// WithLogger sets logger of server, logger of level INFO writing to stdout is used by default
+func WithLogger(l *logger.Logger) Option {
+	return func(s *Server) {
+		s.Logger = l
+		s.Handlers.Logger = l
+	}
+}
 
-	handler := middleware.Logging(mux, s.Logger)
+// WithLatency sets simulated delay of responses
+func WithLatency(l api.Latency) Option {
+	return func(s *Server) {
+		s.Handlers.API.Latency = l
+	}
+}
+
+// WithChaos sets failures injected into responses
+func WithChaos(c api.Chaos) Option {
+	return func(s *Server) {
+		s.Handlers.API.Chaos = c
+	}
+}
 
+// WithSeed sets seed of random choices of server, e.g. weighted examples, latency and injected failures,
+// so same requests always yield same responses
+func WithSeed(seed int64) Option {
+	return func(s *Server) {
+		s.Handlers.Rand = api.NewRand(seed)
+	}
+}
+
+// New returns server of API for embedding, e.g. into integration tests
+func New(a api.API, opts ...Option) *Server {
+	l := logger.NewLogger("")
+
+	s := NewServer(config.Server{}, l, NewHandlers(a, l))
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run -.
+func (s *Server) Run() error {
 	s.Server = &http.Server{
 		Addr:    ":" + s.Config.Port,
-		Handler: handler,
+		Handler: s.handler(),
 	}
 
 	s.Logger.Info().Msgf("Running mock server on %s port", s.Config.Port)
@@ -49,6 +92,54 @@ func (s *Server) Run() error {
 	return nil
 }
 
-func (s *Server) Stop(ctx context.Context) error {
+// Start listens on addr, e.g. `127.0.0.1:0` for random port, and serves requests in background until Shutdown
+func (s *Server) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.Server = &http.Server{
+		Addr:    ln.Addr().String(),
+		Handler: s.handler(),
+	}
+
+	go func() {
+		if err := s.Server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			s.Logger.Error().Err(err).Msg("serve")
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns address server listens on, empty before start
+func (s *Server) Addr() string {
+	if nil == s.Server {
+		return ""
+	}
+
+	return s.Server.Addr
+}
+
+// Shutdown gracefully stops server, waiting for active requests until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	if nil == s.Server {
+		return nil
+	}
+
 	return s.Server.Shutdown(ctx)
 }
+
+func (s *Server) Stop(ctx context.Context) error {
+	return s.Shutdown(ctx)
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", s.Handler)
+	mux.HandleFunc(ExamplesPath, s.ExamplesHandler)
+
+	return middleware.Logging(middleware.Gzip(mux, middleware.GzipMinSize), s.Logger)
+}
@@ -0,0 +1,48 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/parse"
+	"github.com/neotoolkit/dummy/internal/server"
+)
+
+func TestServer_Start(t *testing.T) {
+	a, err := parse.Parse("./testdata/status-codes.yml")
+	require.NoError(t, err)
+
+	s := server.New(a,
+		server.WithSeed(42),
+		server.WithLatency(api.Latency{Min: time.Millisecond}),
+		server.WithChaos(api.Chaos{}),
+	)
+	require.Empty(t, s.Addr())
+
+	require.NoError(t, s.Start("127.0.0.1:0"))
+	require.NotEmpty(t, s.Addr())
+
+	resp, err := http.Get("http://" + s.Addr() + "/users/1")
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.JSONEq(t, `{"firstName":"Elon"}`, string(body))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, s.Shutdown(ctx))
+
+	_, err = http.Get("http://" + s.Addr() + "/users/1")
+	require.Error(t, err)
+}
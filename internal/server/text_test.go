@@ -0,0 +1,57 @@
+package server_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Handler_Text(t *testing.T) {
+	s := newServer(t, "./testdata/text.yml")
+
+	tests := []struct {
+		name        string
+		path        string
+		statusCode  int
+		contentType string
+		want        string
+	}{
+		{
+			name:        "html example",
+			path:        "/page",
+			statusCode:  http.StatusOK,
+			contentType: "text/html",
+			want:        "<h1>Hello</h1>",
+		},
+		{
+			name:        "plain text of schema example",
+			path:        "/count",
+			statusCode:  http.StatusOK,
+			contentType: "text/plain",
+			want:        "42",
+		},
+		{
+			name:        "xml based media type",
+			path:        "/problem",
+			statusCode:  http.StatusBadRequest,
+			contentType: "application/problem+xml",
+			want:        xml.Header + "<problem><title>Bad Request</title></problem>",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+			s.Handler(w, r)
+
+			require.Equal(t, tc.statusCode, w.Code)
+			require.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tc.want, w.Body.String())
+		})
+	}
+}
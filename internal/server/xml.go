@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/neotoolkit/dummy/internal/api"
+)
+
+// xmlItemName is element name of root array items
+const xmlItemName = "item"
+
+// marshalXML returns XML document of response body. Object properties become child elements,
+// named by `xml.name` overrides of schema, and array elements become repeated elements.
+// String body is XML document written as is.
+func marshalXML(response api.Response, body interface{}) ([]byte, error) {
+	if doc, ok := body.(string); ok {
+		return []byte(doc), nil
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+
+	name := response.XMLName
+	if name == "" {
+		name = "response"
+	}
+
+	var err error
+
+	// root array is wrapped, since XML document has single root element
+	if items, ok := xmlItems(body); ok {
+		err = encodeWrapped(enc, name, response.Schema, items)
+	} else {
+		err = encodeXML(enc, name, response.Schema, body)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeWrapped(enc *xml.Encoder, name string, schema api.Schema, items []interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	arr, _ := schema.(api.ArraySchema)
+
+	itemName := arr.XMLItemName
+	if itemName == "" {
+		itemName = xmlItemName
+	}
+
+	for _, item := range items {
+		if err := encodeXML(enc, itemName, arr.Type, item); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+func encodeXML(enc *xml.Encoder, name string, schema api.Schema, value interface{}) error {
+	if items, ok := xmlItems(value); ok {
+		arr, _ := schema.(api.ArraySchema)
+
+		if arr.XMLWrapped {
+			return encodeWrapped(enc, name, arr, items)
+		}
+
+		itemName := arr.XMLItemName
+		if itemName == "" {
+			itemName = name
+		}
+
+		for _, item := range items {
+			if err := encodeXML(enc, itemName, arr.Type, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		if nil == value {
+			return enc.EncodeElement("", start)
+		}
+
+		return enc.EncodeElement(value, start)
+	}
+
+	objSchema, _ := schema.(api.ObjectSchema)
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	elements := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		if !objSchema.XMLAttributes[key] {
+			elements = append(elements, key)
+
+			continue
+		}
+
+		if nil == obj[key] {
+			continue
+		}
+
+		start.Attr = append(start.Attr, xml.Attr{
+			Name:  xml.Name{Local: xmlPropertyName(objSchema, key)},
+			Value: fmt.Sprint(obj[key]),
+		})
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, key := range elements {
+		if err := encodeXML(enc, xmlPropertyName(objSchema, key), objSchema.Properties[key], obj[key]); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlPropertyName returns name of XML element or attribute of object property, overridden by `xml.name`
+func xmlPropertyName(obj api.ObjectSchema, key string) string {
+	if name, ok := obj.XMLNames[key]; ok {
+		return name
+	}
+
+	return key
+}
+
+// xmlItems returns elements of array value
+func xmlItems(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case api.Items:
+		return v.Slice(), true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+
+		return items, true
+	default:
+		return nil, false
+	}
+}
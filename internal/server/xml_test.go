@@ -0,0 +1,56 @@
+package server_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Handler_XML(t *testing.T) {
+	s := newServer(t, "./testdata/xml.yml")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "object",
+			path: "/users/1",
+			want: xml.Header + `<User><age>50</age><first-name>Elon</first-name><tags>founder</tags><tags>engineer</tags></User>`,
+		},
+		{
+			name: "array",
+			path: "/users",
+			want: xml.Header + `<users><item><first-name>Elon</first-name></item><item><first-name>Sergey</first-name></item></users>`,
+		},
+		{
+			name: "attributes and wrapped array",
+			path: "/orders/1",
+			want: xml.Header + `<order cur="USD" id="7"><items><item>book</item><item>pen</item></items><note>fragile</note></order>`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.Header.Set("Accept", "application/xml")
+
+			s.Handler(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+			require.Equal(t, tc.want, w.Body.String())
+
+			var doc struct {
+				XMLName xml.Name
+			}
+
+			require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &doc))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+)
+
+// Problem is a specification issue found by dry-run validation
+type Problem struct {
+	Method  string
+	Path    string
+	Message string
+}
+
+// String -.
+func (p Problem) String() string {
+	return p.Method + " " + p.Path + ": " + p.Message
+}
+
+// Error -.
+type Error struct {
+	Problems []Problem
+}
+
+// Error -.
+func (e *Error) Error() string {
+	return fmt.Sprintf("specification has %d problem(s)", len(e.Problems))
+}
+
+// Validate returns problems found in specification, sorted by path and method
+func Validate(oapi openapi.OpenAPI) []Problem {
+	var problems []Problem
+
+	for path, p := range oapi.Paths {
+		if nil == p {
+			continue
+		}
+
+		for method, o := range operations(p) {
+			problems = append(problems, pathParams(method, path, p.Parameters, o.Parameters)...)
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Path != problems[j].Path {
+			return problems[i].Path < problems[j].Path
+		}
+
+		if problems[i].Method != problems[j].Method {
+			return problems[i].Method < problems[j].Method
+		}
+
+		return problems[i].Message < problems[j].Message
+	})
+
+	return problems
+}
+
+func operations(p *openapi.Path) map[string]*openapi.Operation {
+	all := map[string]*openapi.Operation{
+		"GET":     p.Get,
+		"PUT":     p.Put,
+		"POST":    p.Post,
+		"DELETE":  p.Delete,
+		"OPTIONS": p.Options,
+		"HEAD":    p.Head,
+		"PATCH":   p.Patch,
+		"TRACE":   p.Trace,
+	}
+
+	res := make(map[string]*openapi.Operation, len(all))
+
+	for method, o := range all {
+		if o != nil {
+			res[method] = o
+		}
+	}
+
+	return res
+}
+
+// pathParams cross-checks path template segments with declared path parameters
+func pathParams(method, path string, pathLevel, operationLevel openapi.Parameters) []Problem {
+	var problems []Problem
+
+	template := make(map[string]struct{})
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			// optional and catch-all parameters are declared without question mark and `...` or `+` suffix
+			name := segment[1 : len(segment)-1]
+			for _, suffix := range []string{"?", "...", "+"} {
+				name = strings.TrimSuffix(name, suffix)
+			}
+
+			template[name] = struct{}{}
+		}
+	}
+
+	declared := make(map[string]struct{})
+
+	for _, params := range []openapi.Parameters{pathLevel, operationLevel} {
+		for _, param := range params {
+			if param.In == "path" {
+				declared[param.Name] = struct{}{}
+			}
+		}
+	}
+
+	for name := range template {
+		if _, ok := declared[name]; !ok {
+			problems = append(problems, Problem{
+				Method:  method,
+				Path:    path,
+				Message: "path parameter {" + name + "} is not declared",
+			})
+		}
+	}
+
+	for name := range declared {
+		if _, ok := template[name]; !ok {
+			problems = append(problems, Problem{
+				Method:  method,
+				Path:    path,
+				Message: "declared path parameter " + name + " is not used in path",
+			})
+		}
+	}
+
+	return problems
+}
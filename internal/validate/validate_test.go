@@ -0,0 +1,52 @@
+package validate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/openapi"
+	"github.com/neotoolkit/dummy/internal/read"
+	"github.com/neotoolkit/dummy/internal/validate"
+)
+
+func TestError(t *testing.T) {
+	got := &validate.Error{
+		Problems: []validate.Problem{{}, {}},
+	}
+
+	require.Equal(t, got.Error(), "specification has 2 problem(s)")
+}
+
+func TestProblem_String(t *testing.T) {
+	got := validate.Problem{
+		Method:  "GET",
+		Path:    "/users",
+		Message: "message",
+	}
+
+	require.Equal(t, "GET /users: message", got.String())
+}
+
+func TestValidate(t *testing.T) {
+	file, err := read.Read("./testdata/path-params.yml")
+	require.NoError(t, err)
+
+	oapi, err := openapi.Parse(file)
+	require.NoError(t, err)
+
+	got := validate.Validate(oapi)
+
+	require.Equal(t, []validate.Problem{
+		{
+			Method:  "GET",
+			Path:    "/users/{id}",
+			Message: "declared path parameter userId is not used in path",
+		},
+		{
+			Method:  "GET",
+			Path:    "/users/{id}",
+			Message: "path parameter {id} is not declared",
+		},
+	}, got)
+}
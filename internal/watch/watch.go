@@ -0,0 +1,60 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/logger"
+)
+
+// File calls fn on each change of modification time or size of file at path, file is checked every interval
+// until ctx is done. Error is returned if file can not be checked initially, e.g. for remote specification.
+func File(ctx context.Context, path string, interval time.Duration, fn func()) error {
+	last, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				// file may be missing while editor replaces it
+				continue
+			}
+
+			if info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+
+			last = info
+
+			fn()
+		}
+	}
+}
+
+// Reload stores API returned by load to live on each change of specification file at path.
+// Current API is kept and error is logged when load fails.
+func Reload(ctx context.Context, path string, interval time.Duration, live *api.Holder, load func() (api.API, error), l *logger.Logger) error {
+	return File(ctx, path, interval, func() {
+		a, err := load()
+		if err != nil {
+			l.Error().Err(err).Msg("reload specification")
+
+			return
+		}
+
+		live.Store(live.Load().WithOperations(a))
+
+		l.Info().Msgf("reloaded specification %s", path)
+	})
+}
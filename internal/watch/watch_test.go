@@ -0,0 +1,114 @@
+package watch_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neotoolkit/dummy/internal/api"
+	"github.com/neotoolkit/dummy/internal/config"
+	"github.com/neotoolkit/dummy/internal/logger"
+	"github.com/neotoolkit/dummy/internal/parse"
+	"github.com/neotoolkit/dummy/internal/server"
+	"github.com/neotoolkit/dummy/internal/watch"
+)
+
+const spec = `openapi: 3.0.3
+info:
+  title: Watch dummy API
+  version: 0.1.0
+paths:
+  /status:
+    get:
+      responses:
+        '200':
+          description: ''
+          content:
+            application/json:
+              schema:
+                type: object
+              example:
+                status: %s
+`
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yml")
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+
+	go func() {
+		_ = watch.File(ctx, path, 10*time.Millisecond, func() {
+			changes <- struct{}{}
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0o600))
+
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("change is not detected")
+	}
+
+	err := watch.File(ctx, filepath.Join(t.TempDir(), "missing.yml"), time.Millisecond, func() {})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yml")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(spec, "starting")), 0o600))
+
+	a, err := parse.Parse(path)
+	require.NoError(t, err)
+
+	l := logger.NewLogger("")
+
+	h := server.NewHandlers(a, l)
+	h.Live = api.NewHolder(a)
+	s := server.NewServer(config.Server{}, l, h)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	load := func() (api.API, error) {
+		return parse.Parse(path)
+	}
+
+	go func() {
+		_ = watch.Reload(ctx, path, 10*time.Millisecond, h.Live, load, l)
+	}()
+
+	status := func() string {
+		w := httptest.NewRecorder()
+		s.Handler(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		return w.Body.String()
+	}
+
+	require.JSONEq(t, `{"status": "starting"}`, status())
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(spec, "running")), 0o600))
+
+	require.Eventually(t, func() bool {
+		return status() == `{"status":"running"}`
+	}, time.Second, 10*time.Millisecond)
+
+	// invalid specification keeps served API
+	require.NoError(t, os.WriteFile(path, []byte("openapi: ["), 0o600))
+	time.Sleep(100 * time.Millisecond)
+
+	require.JSONEq(t, `{"status": "running"}`, status())
+}